@@ -0,0 +1,22 @@
+package quarantine
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// logAttr replicates the functionality of testing.TB.Attr() for compatibility with older Go versions.
+// It emits a test attribute in the same format as the native Attr method.
+func logAttr(tb testing.TB, key, value string) {
+	if strings.ContainsFunc(key, unicode.IsSpace) {
+		tb.Errorf("disallowed whitespace in attribute key %q", key)
+		return
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		tb.Errorf("disallowed newline in attribute value %q", value)
+		return
+	}
+	// Emit the attribute in the same format as testing.TB.Attr()
+	tb.Logf("=== ATTR  %s %s %s", tb.Name(), key, value)
+}
@@ -0,0 +1,20 @@
+//go:build go1.25
+
+package quarantine
+
+import "testing"
+
+// attrer is implemented by testing.TB from Go 1.25 onwards.
+type attrer interface {
+	Attr(key, value string)
+}
+
+// attr emits a test attribute with the native testing.TB.Attr, so it becomes a proper test2json attr event.
+// It falls back to logAttr for testing.TB implementations that don't support it.
+func attr(tb testing.TB, key, value string) {
+	if a, ok := tb.(attrer); ok {
+		a.Attr(key, value)
+		return
+	}
+	logAttr(tb, key, value)
+}
@@ -0,0 +1,17 @@
+//go:build go1.25
+
+package quarantine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttrNative(t *testing.T) {
+	tb := newAttrTB(t)
+	attr(tb, "flaky", "TEST-123")
+
+	require.Equal(t, map[string]string{"flaky": "TEST-123"}, tb.attrs, "attr should use the native Attr method")
+	require.Empty(t, tb.logs, "attr should not fall back to logging")
+}
@@ -0,0 +1,10 @@
+//go:build !go1.25
+
+package quarantine
+
+import "testing"
+
+// attr emits a test attribute by logging it, as testing.TB.Attr is only available from Go 1.25 onwards.
+func attr(tb testing.TB, key, value string) {
+	logAttr(tb, key, value)
+}
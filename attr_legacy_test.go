@@ -0,0 +1,17 @@
+//go:build !go1.25
+
+package quarantine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttrLegacy(t *testing.T) {
+	tb := newAttrTB(t)
+	attr(tb, "flaky", "TEST-123")
+
+	require.Equal(t, []string{"=== ATTR  TestAttrLegacy flaky TEST-123"}, tb.logs, "attr should log the attribute")
+	require.Empty(t, tb.attrs)
+}
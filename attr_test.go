@@ -0,0 +1,59 @@
+package quarantine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// attrTB captures what attr and logAttr emit on a test.
+type attrTB struct {
+	testing.TB
+
+	logs   []string
+	errors []string
+	attrs  map[string]string
+}
+
+func newAttrTB(t *testing.T) *attrTB {
+	return &attrTB{TB: t, attrs: map[string]string{}}
+}
+
+func (a *attrTB) Logf(format string, args ...any) {
+	a.logs = append(a.logs, fmt.Sprintf(format, args...))
+}
+
+func (a *attrTB) Errorf(format string, args ...any) {
+	a.errors = append(a.errors, fmt.Sprintf(format, args...))
+}
+
+func (a *attrTB) Attr(key, value string) {
+	a.attrs[key] = value
+}
+
+func TestLogAttr(t *testing.T) {
+	t.Run("emit attribute", func(t *testing.T) {
+		tb := newAttrTB(t)
+		logAttr(tb, "flaky", "TEST-123")
+
+		require.Equal(t, []string{"=== ATTR  TestLogAttr/emit_attribute flaky TEST-123"}, tb.logs)
+		require.Empty(t, tb.errors)
+	})
+
+	t.Run("reject whitespace in key", func(t *testing.T) {
+		tb := newAttrTB(t)
+		logAttr(tb, "root cause", "race")
+
+		require.Empty(t, tb.logs)
+		require.Len(t, tb.errors, 1)
+	})
+
+	t.Run("reject newline in value", func(t *testing.T) {
+		tb := newAttrTB(t)
+		logAttr(tb, "reason", "first\nsecond")
+
+		require.Empty(t, tb.logs)
+		require.Len(t, tb.errors, 1)
+	})
+}
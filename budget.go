@@ -0,0 +1,153 @@
+package quarantine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	// BudgetEnvVar is the environment variable that caps the total time spent running enabled quarantined tests,
+	// as a duration like "10m". Once the budget is spent, further quarantined tests are skipped.
+	BudgetEnvVar = "QUARANTINE_BUDGET"
+	// BudgetFileEnvVar is the environment variable that points to the file tracking time spent against the budget.
+	// The file is locked while updating, so every package binary in go test ./... can share it.
+	// Use a fresh file for each CI run, as time spent is never reset.
+	BudgetFileEnvVar = "QUARANTINE_BUDGET_FILE"
+)
+
+// budgetState is the contents of the budget file.
+type budgetState struct {
+	SpentSeconds float64 `json:"spent_seconds"`
+}
+
+var (
+	budgetMu sync.Mutex
+	// chargedTests are the names of the tests in this process whose time is being charged to the budget.
+	chargedTests = map[string]bool{}
+)
+
+// budgetCharge adds a test's run time to the time spent once it finishes.
+type budgetCharge struct {
+	budgetFile string
+}
+
+// checkBudget skips the test if the time budget for quarantined tests has already been spent, reporting whether
+// it did. Otherwise it returns the charge to start once the test actually starts running, or nil if the test isn't
+// charged. Isolated subprocesses are left to their parent, which accounts for the whole subprocess, and subtests
+// of a test that is already charged are left to it, so the same time isn't charged twice.
+func checkBudget(tb testing.TB) (*budgetCharge, bool) {
+	tb.Helper()
+
+	value := os.Getenv(BudgetEnvVar)
+	if value == "" || isolatedChild(tb) || charged(tb.Name()) {
+		return nil, false
+	}
+	budget, err := time.ParseDuration(value)
+	if err != nil {
+		tb.Fatalf("Invalid value for %s: %v", BudgetEnvVar, err)
+		return nil, false
+	}
+	budgetFile := os.Getenv(BudgetFileEnvVar)
+	if budgetFile == "" {
+		tb.Fatalf("%s must be set to a file shared by the test run when %s is set", BudgetFileEnvVar, BudgetEnvVar)
+		return nil, false
+	}
+
+	spent, err := spendBudget(budgetFile, 0)
+	if err != nil {
+		tb.Fatalf("Failed to read quarantine budget from %s: %v", budgetFile, err)
+		return nil, false
+	}
+	if spent >= budget {
+		attr(tb, "budget", "exhausted")
+		tb.Skipf(
+			"Quarantine time budget exhausted, %s of %s already spent running quarantined tests. To raise it, set %s.",
+			spent.Round(time.Millisecond),
+			budget,
+			BudgetEnvVar,
+		)
+		return nil, true
+	}
+	return &budgetCharge{budgetFile: budgetFile}, false
+}
+
+// start charges the test's time from now until it finishes.
+func (c *budgetCharge) start(tb testing.TB) {
+	if c == nil {
+		return
+	}
+
+	budgetMu.Lock()
+	chargedTests[tb.Name()] = true
+	budgetMu.Unlock()
+
+	start := time.Now()
+	tb.Cleanup(func() {
+		budgetMu.Lock()
+		delete(chargedTests, tb.Name())
+		budgetMu.Unlock()
+		if _, err := spendBudget(c.budgetFile, time.Since(start)); err != nil {
+			tb.Errorf("Failed to write quarantine budget to %s: %v", c.budgetFile, err)
+		}
+	})
+}
+
+// charged reports whether the named test, or one of its parents, is already charged to the budget.
+func charged(testName string) bool {
+	budgetMu.Lock()
+	defer budgetMu.Unlock()
+
+	for name := range chargedTests {
+		if isTestOrSubtest(testName, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// spendBudget adds d to the time spent recorded in the budget file, and returns the new total.
+func spendBudget(budgetFile string, d time.Duration) (time.Duration, error) {
+	// #nosec G304 - path is provided by the user running the tests
+	f, err := os.OpenFile(budgetFile, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if err := lockFile(f); err != nil {
+		return 0, fmt.Errorf("failed to lock: %w", err)
+	}
+	defer func() { _ = unlockFile(f) }()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return 0, err
+	}
+	var state budgetState
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &state); err != nil {
+			return 0, err
+		}
+	}
+	if d == 0 {
+		return time.Duration(state.SpentSeconds * float64(time.Second)), nil
+	}
+
+	state.SpentSeconds += d.Seconds()
+	data, err = json.Marshal(state)
+	if err != nil {
+		return 0, err
+	}
+	if err := f.Truncate(0); err != nil {
+		return 0, err
+	}
+	if _, err := f.WriteAt(data, 0); err != nil {
+		return 0, err
+	}
+	return time.Duration(state.SpentSeconds * float64(time.Second)), nil
+}
@@ -0,0 +1,103 @@
+package quarantine_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestBudget(t *testing.T) {
+	t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "true")
+	t.Setenv(quarantine.BudgetEnvVar, "100ms")
+
+	t.Run("exhausted", func(t *testing.T) {
+		t.Setenv(quarantine.BudgetFileEnvVar, filepath.Join(t.TempDir(), "budget.json"))
+
+		first := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+			time.Sleep(150 * time.Millisecond)
+		})
+		require.False(t, first.Skipped(), "test should run while the budget has time left")
+
+		second := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+		require.True(t, second.Skipped(), "test should be skipped once the budget is spent")
+		require.Equal(t, "exhausted", second.Attrs()["budget"])
+		require.Contains(t, second.Logs(), "budget exhausted")
+	})
+
+	t.Run("shared file", func(t *testing.T) {
+		budgetFile := filepath.Join(t.TempDir(), "budget.json")
+		require.NoError(t, os.WriteFile(budgetFile, []byte(`{"spent_seconds": 0.2}`), 0600))
+		t.Setenv(quarantine.BudgetFileEnvVar, budgetFile)
+
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+		require.True(t, fake.Skipped(), "time spent by other test binaries should count against the budget")
+	})
+
+	t.Run("skipped tests are free", func(t *testing.T) {
+		budgetFile := filepath.Join(t.TempDir(), "budget.json")
+		t.Setenv(quarantine.BudgetFileEnvVar, budgetFile)
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+
+		runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+		_, err := os.Stat(budgetFile)
+		require.ErrorIs(t, err, os.ErrNotExist, "skipped tests should not touch the budget")
+	})
+
+	t.Run("charge nested quarantines once", func(t *testing.T) {
+		t.Setenv(quarantine.BudgetEnvVar, "1h")
+		budgetFile := filepath.Join(t.TempDir(), "budget.json")
+		t.Setenv(quarantine.BudgetFileEnvVar, budgetFile)
+
+		t.Run("parent", func(t *testing.T) {
+			quarantine.Flaky(t, "TEST-1")
+			quarantine.Run(t, "row", "TEST-2", func(*testing.T) {
+				time.Sleep(200 * time.Millisecond)
+			})
+		})
+
+		var state struct {
+			SpentSeconds float64 `json:"spent_seconds"`
+		}
+		data, err := os.ReadFile(budgetFile)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &state))
+		require.GreaterOrEqual(t, state.SpentSeconds, 0.2)
+		require.Less(t, state.SpentSeconds, 0.4, "the subtest's time should only be charged to its parent")
+	})
+
+	t.Run("check budget before waiting for a slot", func(t *testing.T) {
+		budgetFile := filepath.Join(t.TempDir(), "budget.json")
+		require.NoError(t, os.WriteFile(budgetFile, []byte(`{"spent_seconds": 0.2}`), 0600))
+		t.Setenv(quarantine.BudgetFileEnvVar, budgetFile)
+		t.Setenv(quarantine.SerializeEnvVar, "true")
+		t.Setenv(quarantine.LockDirEnvVar, t.TempDir())
+
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+		require.True(t, fake.Skipped())
+		require.NotContains(t, fake.Attrs(), "serialize_wait", "exhausted budget should skip before taking a slot")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv(quarantine.BudgetFileEnvVar, "")
+
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+		require.True(t, fake.Failed(), "budget without a file should fail the test")
+	})
+}
@@ -0,0 +1,104 @@
+package quarantine
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"unicode"
+)
+
+// Classification is a kind of quarantine, gated by its own environment variable.
+// Flaky and Timeout are built in, and more can be added with Register.
+type Classification struct {
+	name        string
+	envVar      string
+	skipInShort bool
+}
+
+// Name returns the classification's name, which is also the key of the attribute it emits.
+func (c *Classification) Name() string {
+	return c.name
+}
+
+// EnvVar returns the environment variable that controls whether tests with this classification run.
+func (c *Classification) EnvVar() string {
+	return c.envVar
+}
+
+// ClassificationOption configures a Classification.
+type ClassificationOption func(*Classification)
+
+// SkipInShort also skips tests with the classification when running with -short, regardless of its env var.
+func SkipInShort() ClassificationOption {
+	return func(c *Classification) {
+		c.skipInShort = true
+	}
+}
+
+var (
+	classificationsMu sync.Mutex
+	classifications   = map[string]*Classification{}
+)
+
+var (
+	// FlakyClassification is the classification applied by Flaky.
+	FlakyClassification = Register("flaky", RunQuarantinedTestsEnvVar)
+	// TimeoutClassification is the classification applied by Timeout.
+	TimeoutClassification = Register("timeout", RunTimeoutTestsEnvVar)
+)
+
+// Register defines a new classification gated by envVar, and accepts the same values as RUN_QUARANTINED_TESTS.
+// It is meant to be called from a package-level var, and panics if the name is invalid or already registered.
+// Registered classifications can also be used in the registry file.
+//
+// Example:
+//
+//	var Slow = quarantine.Register("slow", "RUN_SLOW_TESTS", quarantine.SkipInShort())
+//
+//	func TestSlow(t *testing.T) {
+//		quarantine.Classify(t, Slow, "TEST-123")
+//	}
+func Register(name, envVar string, opts ...ClassificationOption) *Classification {
+	if name == "" || strings.ContainsFunc(name, unicode.IsSpace) {
+		panic(fmt.Sprintf("quarantine: invalid classification name %q", name))
+	}
+	if envVar == "" {
+		panic(fmt.Sprintf("quarantine: classification %q has no env var", name))
+	}
+
+	class := &Classification{name: name, envVar: envVar}
+	for _, opt := range opts {
+		opt(class)
+	}
+
+	classificationsMu.Lock()
+	defer classificationsMu.Unlock()
+	if _, exists := classifications[name]; exists {
+		panic(fmt.Sprintf("quarantine: classification %q already registered", name))
+	}
+	classifications[name] = class
+	return class
+}
+
+// lookupClassification returns the registered classification with the given name.
+func lookupClassification(name string) (*Classification, bool) {
+	classificationsMu.Lock()
+	defer classificationsMu.Unlock()
+	class, ok := classifications[name]
+	return class, ok
+}
+
+// Classify marks a test with a classification, skipping it unless the classification's env var enables it.
+// It accepts the same options as Flaky.
+//
+// Example:
+//
+//	func TestNeedsDocker(t *testing.T) {
+//		quarantine.Classify(t, NeedsDocker, "TEST-123")
+//	}
+func Classify(tb testing.TB, class *Classification, ticket string, opts ...Option) {
+	tb.Helper()
+
+	skipTest(tb, class, ticket, newOptions(opts))
+}
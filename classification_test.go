@@ -0,0 +1,72 @@
+package quarantine_test
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+var slow = quarantine.Register("slow", "RUN_SLOW_TESTS", quarantine.SkipInShort())
+
+func TestClassify(t *testing.T) {
+	t.Run("skip custom classification", func(t *testing.T) {
+		t.Setenv(slow.EnvVar(), "false")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Classify(tb, slow, "TEST-123")
+		})
+
+		require.True(t, fake.Skipped(), "slow test should be skipped when RUN_SLOW_TESTS is false")
+		require.Equal(t, "TEST-123", fake.Attrs()["slow"])
+	})
+
+	t.Run("run custom classification", func(t *testing.T) {
+		t.Setenv(slow.EnvVar(), "true")
+		quarantine.Classify(t, slow, "TEST-123")
+
+		t.Cleanup(func() {
+			require.False(t, t.Skipped(), "slow test should not be skipped when RUN_SLOW_TESTS is true")
+		})
+	})
+
+	t.Run("skip in short mode", func(t *testing.T) {
+		t.Setenv(slow.EnvVar(), "true")
+		setShort(t)
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Classify(tb, slow, "TEST-123")
+		})
+
+		require.True(t, fake.Skipped(), "slow test should be skipped in short mode")
+	})
+
+	t.Run("registry", func(t *testing.T) {
+		writeRegistry(t, `[{"test": "TestClassify/registry", "classification": "slow", "ticket": "TEST-123"}]`)
+		t.Setenv(slow.EnvVar(), "false")
+		quarantine.Check(t)
+
+		t.Cleanup(func() {
+			require.True(t, t.Skipped(), "registered slow test should be skipped when RUN_SLOW_TESTS is false")
+		})
+	})
+}
+
+func TestRegister(t *testing.T) {
+	require.Equal(t, "flaky", quarantine.FlakyClassification.Name())
+	require.Equal(t, quarantine.RunTimeoutTestsEnvVar, quarantine.TimeoutClassification.EnvVar())
+	require.Panics(t, func() { quarantine.Register("flaky", "OTHER_ENV_VAR") }, "duplicate name should panic")
+	require.Panics(t, func() { quarantine.Register("needs docker", "RUN_DOCKER_TESTS") }, "whitespace should panic")
+	require.Panics(t, func() { quarantine.Register("broken", "") }, "missing env var should panic")
+}
+
+// setShort enables -test.short for the rest of the test.
+func setShort(t *testing.T) {
+	t.Helper()
+
+	previous := flag.Lookup("test.short").Value.String()
+	require.NoError(t, flag.Set("test.short", "true"))
+	t.Cleanup(func() {
+		require.NoError(t, flag.Set("test.short", previous))
+	})
+}
@@ -199,6 +199,12 @@ func main() {
 
 			if tCase.Failure != nil {
 				writeRawLogFile(logger, testLogsOutputDir, tCase)
+				if downgradeReportOnlyFailure(&tCase) {
+					logger.Info("Downgrading report-only quarantined failure %s to skipped", tCase.Name)
+					suite.Failures--
+					suite.Skipped++
+					testSuites.Failures--
+				}
 			}
 
 			// Process file information for valid test cases
@@ -241,6 +247,23 @@ func main() {
 	logger.Info("Successfully enhanced JUnit XML file: %s (%d/%d test cases matched)", *outputFile, matched, total)
 }
 
+// reportOnlyMarker is logged by the quarantine package when a quarantined test fails in report-only mode.
+// Keep in sync with quarantine.ReportOnlyMarker.
+const reportOnlyMarker = "quarantine: report-only failure"
+
+// downgradeReportOnlyFailure turns the failure of a quarantined test that ran in report-only mode into a skip,
+// so it does not fail the build. Returns true if the test case was downgraded.
+func downgradeReportOnlyFailure(tCase *JUnitTestCase) bool {
+	if tCase.Failure == nil || !strings.Contains(tCase.Failure.Contents, reportOnlyMarker) {
+		return false
+	}
+	tCase.SkipMessage = &JUnitSkipMessage{
+		Message: "Quarantined test failed in report-only mode: " + tCase.Failure.Message,
+	}
+	tCase.Failure = nil
+	return true
+}
+
 // writeRawLogFile writes a raw log file for a single failed test for easier debugging by other tools and CI systems
 // The file is written to a subdirectory of the base output directory called "raw-test-logs"
 func writeRawLogFile(logger *Logger, baseOutputDir string, failingTest JUnitTestCase) {
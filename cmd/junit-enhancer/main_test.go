@@ -51,3 +51,32 @@ func TestJUnitXMLProcessing(t *testing.T) {
 		t.Errorf("Expected file attribute in output, got: %s", outputStr)
 	}
 }
+
+func TestDowngradeReportOnlyFailure(t *testing.T) {
+	t.Parallel()
+
+	reportOnly := JUnitTestCase{
+		Name: "TestFlaky",
+		Failure: &JUnitFailure{
+			Message:  "Failed",
+			Contents: "flaky_test.go:12: boom\n    quarantine.go:120: " + reportOnlyMarker + ": test is marked as flaky",
+		},
+	}
+	if !downgradeReportOnlyFailure(&reportOnly) {
+		t.Fatal("Expected report-only failure to be downgraded")
+	}
+	if reportOnly.Failure != nil || reportOnly.SkipMessage == nil {
+		t.Errorf("Expected report-only failure to become a skip, got %+v", reportOnly)
+	}
+
+	regular := JUnitTestCase{
+		Name:    "TestBroken",
+		Failure: &JUnitFailure{Message: "Failed", Contents: "broken_test.go:12: boom"},
+	}
+	if downgradeReportOnlyFailure(&regular) {
+		t.Error("Expected regular failure not to be downgraded")
+	}
+	if regular.Failure == nil {
+		t.Error("Expected regular failure to be kept")
+	}
+}
@@ -0,0 +1,85 @@
+package quarantine
+
+import (
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// condition restricts a quarantine to the environments where the test is known to flake.
+type condition struct {
+	name    string
+	matches func() bool
+}
+
+// withCondition returns an option adding the condition, so conditions can be passed to FlakyWhen along with
+// other options.
+func withCondition(name string, matches func() bool) Option {
+	return func(o *options) {
+		o.conditions = append(o.conditions, condition{name: name, matches: matches})
+	}
+}
+
+// OnGOOS matches when running on any of the given operating systems.
+func OnGOOS(goos ...string) Option {
+	return withCondition("goos="+runtime.GOOS, func() bool { return slices.Contains(goos, runtime.GOOS) })
+}
+
+// OnGOARCH matches when running on any of the given architectures.
+func OnGOARCH(goarch ...string) Option {
+	return withCondition("goarch="+runtime.GOARCH, func() bool { return slices.Contains(goarch, runtime.GOARCH) })
+}
+
+// WithRace matches when the test binary was built with the race detector.
+func WithRace() Option {
+	return withCondition("race", func() bool { return raceEnabled })
+}
+
+// OnCI matches when running on a CI system, detected by the CI or GITHUB_ACTIONS environment variables.
+func OnCI() Option {
+	return withCondition("ci", isCI)
+}
+
+// When matches whenever fn returns true. The name is emitted as an attribute when it matches.
+func When(name string, fn func() bool) Option {
+	return withCondition(name, fn)
+}
+
+func isCI() bool {
+	for _, envVar := range []string{"CI", "GITHUB_ACTIONS"} {
+		if ci, err := strconv.ParseBool(os.Getenv(envVar)); err == nil && ci {
+			return true
+		}
+	}
+	return false
+}
+
+// FlakyWhen marks a test as flaky only when any of its conditions match, and runs it normally everywhere else.
+// Conditions are options, and can be mixed with other options, which apply as for Flaky when a condition matches.
+// The first matching condition is emitted as an attribute.
+//
+// Example:
+//
+//	func TestFlakyUnderRace(t *testing.T) {
+//		quarantine.FlakyWhen(t, "TEST-123", quarantine.WithRace(), quarantine.OnGOARCH("arm64"), quarantine.Owner("team-a"))
+//	}
+func FlakyWhen(tb testing.TB, ticket string, opts ...Option) {
+	tb.Helper()
+
+	o := newOptions(opts)
+	if len(o.conditions) == 0 {
+		tb.Fatalf("FlakyWhen for %s has no conditions, use Flaky to always quarantine the test", ticketRef(ticket))
+		return
+	}
+	conditions := o.conditions
+	o.conditions = nil
+	for _, condition := range conditions {
+		if condition.matches() {
+			attr(tb, "quarantine_condition", condition.name)
+			skipTest(tb, FlakyClassification, ticket, o)
+			return
+		}
+	}
+}
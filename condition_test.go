@@ -0,0 +1,83 @@
+package quarantine_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestFlakyWhen(t *testing.T) {
+	t.Run("skip when condition matches", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.FlakyWhen(
+				tb,
+				"TEST-123",
+				quarantine.OnGOOS("plan9"),
+				quarantine.OnGOARCH(runtime.GOARCH),
+				quarantine.Owner("team-a"),
+			)
+		})
+
+		require.True(t, fake.Skipped(), "test should be skipped when a condition matches")
+		require.Equal(t, "goarch="+runtime.GOARCH, fake.Attrs()["quarantine_condition"])
+		require.Equal(t, "TEST-123", fake.Attrs()["flaky"])
+		require.Equal(t, "team-a", fake.Attrs()["owner"], "options should apply when a condition matches")
+	})
+
+	t.Run("run when no condition matches", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.FlakyWhen(
+				tb,
+				"TEST-123",
+				quarantine.OnGOOS("plan9"),
+				quarantine.When("never", func() bool { return false }),
+			)
+		})
+
+		require.False(t, fake.Skipped(), "test should run when no condition matches")
+		require.Empty(t, fake.Attrs())
+	})
+
+	t.Run("ci", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		t.Setenv("CI", "true")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.FlakyWhen(tb, "TEST-123", quarantine.OnCI())
+		})
+
+		require.True(t, fake.Skipped(), "test should be skipped on CI")
+	})
+
+	t.Run("not ci", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		t.Setenv("CI", "false")
+		t.Setenv("GITHUB_ACTIONS", "")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.FlakyWhen(tb, "TEST-123", quarantine.OnCI())
+		})
+
+		require.False(t, fake.Skipped(), "test should run outside of CI")
+	})
+
+	t.Run("no conditions", func(t *testing.T) {
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.FlakyWhen(tb, "TEST-123", quarantine.Owner("team-a"))
+		})
+
+		require.True(t, fake.Failed(), "FlakyWhen without conditions should fail the test")
+	})
+
+	t.Run("conditions passed to Flaky", func(t *testing.T) {
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123", quarantine.OnCI())
+		})
+
+		require.True(t, fake.Failed(), "conditions passed to Flaky should fail the test")
+		require.Contains(t, fake.Logs(), "only apply to FlakyWhen")
+	})
+}
@@ -0,0 +1,179 @@
+package quarantine
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var (
+	// diagnosticsEnvPrefixes are the prefixes of this package's environment variables, included in diagnostics bundles.
+	diagnosticsEnvPrefixes = []string{"QUARANTINE_", "RUN_"}
+	// diagnosticsEnvNames are the other environment variables included in diagnostics bundles.
+	// Bundles are usually uploaded as CI artifacts, so only variables known not to hold credentials are listed.
+	diagnosticsEnvNames = []string{
+		"GOOS", "GOARCH", "GOMAXPROCS", "GOGC", "GOMEMLIMIT", "GODEBUG", "GOTRACEBACK", "CGO_ENABLED",
+		"CI", "GITHUB_ACTIONS", "GITHUB_WORKFLOW", "GITHUB_JOB", "GITHUB_RUN_ID", "GITHUB_RUN_ATTEMPT",
+		"GITHUB_SHA", "GITHUB_REF", "RUNNER_OS", "RUNNER_ARCH",
+	}
+	// diagnosticsRedactedWords mark environment variables whose values are redacted, even when they are included.
+	diagnosticsRedactedWords = []string{"TOKEN", "SECRET", "KEY", "PASSWORD"}
+)
+
+// diagnostics captures a bundle of debugging information for a quarantined test that fails while running.
+type diagnostics struct {
+	artifactDir string
+	tempRoot    string
+	ownTempDir  string
+}
+
+// startDiagnostics prepares to capture diagnostics on failure, or returns nil if no artifact dir is configured.
+// It must be called before the test body runs, so that the test's temp dirs outlive the diagnostics cleanup.
+func startDiagnostics(tb testing.TB) *diagnostics {
+	artifactDir := os.Getenv(ArtifactDirEnvVar)
+	if artifactDir == "" {
+		return nil
+	}
+
+	// Every t.TempDir of a test shares a parent that is removed by a cleanup registered on the first call.
+	// Calling it now makes that cleanup run after ours, so the test's temp dirs can still be copied.
+	ownTempDir := tb.TempDir()
+	return &diagnostics{
+		artifactDir: artifactDir,
+		tempRoot:    filepath.Dir(ownTempDir),
+		ownTempDir:  ownTempDir,
+	}
+}
+
+// capture writes the diagnostics bundle if the test failed, and emits its path as the diagnostics attribute.
+func (d *diagnostics) capture(tb testing.TB) {
+	if d == nil || !tb.Failed() {
+		return
+	}
+
+	bundle := filepath.Join(d.artifactDir, artifactName(tb.Name())+".diagnostics")
+	if err := d.write(bundle); err != nil {
+		tb.Logf("Failed to write diagnostics bundle %s: %v", bundle, err)
+		return
+	}
+	attr(tb, "diagnostics", bundle)
+	tb.Logf("Wrote diagnostics bundle for failed quarantined test to %s", bundle)
+}
+
+func (d *diagnostics) write(bundle string) error {
+	if err := os.MkdirAll(bundle, 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(bundle, "goroutines.txt"), allStacks(), 0600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(bundle, "environment.txt"), []byte(environmentReport()), 0600); err != nil {
+		return err
+	}
+	return copyDir(d.tempRoot, filepath.Join(bundle, "tempdir"), d.ownTempDir, bundle)
+}
+
+// environmentReport describes the runtime, test flags, and relevant environment variables.
+func environmentReport() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "go_version=%s\n", runtime.Version())
+	fmt.Fprintf(&b, "goos=%s\ngoarch=%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "gomaxprocs=%d\nnum_cpu=%d\n", runtime.GOMAXPROCS(0), runtime.NumCPU())
+	fmt.Fprintf(&b, "race=%t\n", raceEnabled)
+	for _, name := range []string{"test.shuffle", "test.count", "test.cpu", "test.parallel", "test.timeout"} {
+		if f := flag.Lookup(name); f != nil {
+			fmt.Fprintf(&b, "%s=%s\n", name, f.Value.String())
+		}
+	}
+
+	b.WriteString("\n")
+	env := os.Environ()
+	sort.Strings(env)
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		if !includeEnv(name) {
+			continue
+		}
+		if redactEnv(name) {
+			kv = name + "=<redacted>"
+		}
+		b.WriteString(kv + "\n")
+	}
+	return b.String()
+}
+
+// includeEnv reports whether the environment variable belongs in diagnostics bundles.
+func includeEnv(name string) bool {
+	if slices.Contains(diagnosticsEnvNames, name) {
+		return true
+	}
+	for _, prefix := range diagnosticsEnvPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactEnv reports whether the environment variable's value may be a credential.
+func redactEnv(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, word := range diagnosticsRedactedWords {
+		if strings.Contains(upper, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyDir copies the regular files and directories under src to dst, skipping the excluded directories.
+func copyDir(src, dst string, exclude ...string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if slices.Contains(exclude, path) {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, 0700)
+		case entry.Type().IsRegular():
+			return copyFile(path, target)
+		default:
+			return nil
+		}
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src) // #nosec G304 - src is inside the test's temp dir
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600) // #nosec G304 - dst is inside the artifact dir
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,39 @@
+package quarantine
+
+import (
+	"os"
+	"sync"
+)
+
+// fileCache reads and parses files, caching the result for the life of the test binary.
+type fileCache[T any] struct {
+	parse func(data []byte) (T, error)
+
+	mu     sync.Mutex
+	parsed map[string]T
+}
+
+func newFileCache[T any](parse func(data []byte) (T, error)) *fileCache[T] {
+	return &fileCache[T]{parse: parse, parsed: map[string]T{}}
+}
+
+// load returns the parsed contents of the file, reading it on first use.
+func (c *fileCache[T]) load(name string) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if parsed, ok := c.parsed[name]; ok {
+		return parsed, nil
+	}
+
+	var parsed T
+	data, err := os.ReadFile(name) // #nosec G304 - path is provided by the user running the tests
+	if err != nil {
+		return parsed, err
+	}
+	if parsed, err = c.parse(data); err != nil {
+		return parsed, err
+	}
+	c.parsed[name] = parsed
+	return parsed, nil
+}
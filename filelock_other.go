@@ -0,0 +1,21 @@
+//go:build !unix
+
+package quarantine
+
+import "os"
+
+// lockFile is a no-op on platforms without flock. Writers should keep to a single write per update,
+// which appending files handle atomically for small writes on most systems.
+func lockFile(*os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op on platforms without flock.
+func unlockFile(*os.File) error {
+	return nil
+}
+
+// tryLockFile is a no-op on platforms without flock, the lock is always taken.
+func tryLockFile(*os.File) (bool, error) {
+	return true, nil
+}
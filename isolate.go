@@ -0,0 +1,151 @@
+package quarantine
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	// IsolateEnvVar is the environment variable that runs every enabled quarantined test in its own subprocess,
+	// the same as passing the Isolate option.
+	IsolateEnvVar = "QUARANTINE_ISOLATE"
+
+	// isolatedTestEnvVar is set on the subprocess to the name of the test it should run in-process.
+	isolatedTestEnvVar = "QUARANTINE_ISOLATED_TEST"
+)
+
+// Isolate runs the quarantined test in a subprocess when it is enabled, so a test that panics, calls os.Exit,
+// deadlocks, or corrupts global state can't take down the rest of the package.
+// The subprocess re-executes the test binary with -test.run matching only this test, and its output is
+// streamed to the test log. Since the rest of the test body already ran in the subprocess, a passing test
+// is reported as skipped in the parent, with an isolated_outcome attribute of pass, unless it uses FlakyFunc.
+// In report-only mode, the parent reports the subprocess's outcome.
+func Isolate() Option {
+	return func(o *options) {
+		o.isolate = true
+	}
+}
+
+// shouldIsolate reports whether the test should be re-executed in a subprocess.
+func shouldIsolate(tb testing.TB, opts *options) bool {
+	if isolatedChild(tb) {
+		return false
+	}
+	isolate, _ := strconv.ParseBool(os.Getenv(IsolateEnvVar))
+	return opts.isolate || isolate
+}
+
+// isolate runs the test in a subprocess and reports its outcome on tb. For quarantines that take the test body as a
+// func, it returns modeIsolated once the subprocess passes, or fails in report-only mode. Otherwise it never returns
+// normally, as the rest of the test body must not run again in the parent.
+func isolate(tb testing.TB, classification string, mode runMode, opts *options) runMode {
+	tb.Helper()
+
+	result := runIsolated(tb, classification)
+	switch {
+	case result == "skip":
+		tb.Skip("Isolated test was skipped")
+	case result == "fail" && mode == modeReport && opts.funcBody:
+		tb.Logf("Isolated test marked as %s failed in report-only mode, which doesn't fail the test", classification)
+		return modeIsolated
+	case result == "fail":
+		tb.Fatal("Isolated test failed")
+	case opts.funcBody:
+		return modeIsolated
+	default:
+		tb.Skip("Isolated test passed in a subprocess, the rest of the test body is not run again")
+	}
+	return modeSkip
+}
+
+// runIsolated runs the test in a subprocess, streaming its output to tb, and returns its outcome.
+// The subprocess runs the test without deciding again whether to, as the parent already did.
+func runIsolated(tb testing.TB, classification string) string {
+	tb.Helper()
+
+	// #nosec G204 - re-executes the running test binary
+	cmd := exec.Command(os.Args[0], isolatedArgs(tb.Name())...)
+	cmd.Env = append(os.Environ(), isolatedTestEnvVar+"="+tb.Name())
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		tb.Fatalf("Failed to run isolated test: %v", err)
+	}
+	cmd.Stderr = cmd.Stdout
+
+	tb.Logf("Running test marked as '%s' in an isolated subprocess.", classification)
+	if err := cmd.Start(); err != nil {
+		tb.Fatalf("Failed to run isolated test: %v", err)
+	}
+
+	var (
+		passLine = "--- PASS: " + tb.Name() + " "
+		skipLine = "--- SKIP: " + tb.Name() + " "
+		passed   bool
+		skipped  bool
+		scanner  = bufio.NewScanner(stdout)
+	)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		passed = passed || strings.HasPrefix(trimmed, passLine)
+		skipped = skipped || strings.HasPrefix(trimmed, skipLine)
+		tb.Log(line)
+	}
+	err = cmd.Wait()
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		tb.Logf("Isolated test failed with exit code %d", exitErr.ExitCode())
+		setIsolatedOutcome(tb, "fail")
+		return "fail"
+	case err != nil:
+		tb.Fatalf("Failed to run isolated test: %v", err)
+	case skipped:
+		setIsolatedOutcome(tb, "skip")
+		return "skip"
+	case passed:
+		setIsolatedOutcome(tb, "pass")
+		return "pass"
+	}
+	tb.Fatalf("Isolated test did not report an outcome, it may not have been found by -test.run")
+	return "fail"
+}
+
+// setIsolatedOutcome records the outcome of the subprocess, which the results file and Main's summary report
+// instead of the parent's outcome, and emits it as the isolated_outcome attribute.
+func setIsolatedOutcome(tb testing.TB, result string) {
+	setBodyOutcome(tb, result)
+	attr(tb, "isolated_outcome", result)
+}
+
+// isolatedChild reports whether this process is the subprocess running the isolated test.
+// The parent records the test's result and accounts for it in the budget and serialization slots,
+// so the subprocess leaves those to it.
+func isolatedChild(tb testing.TB) bool {
+	return os.Getenv(isolatedTestEnvVar) == tb.Name()
+}
+
+// isolatedArgs returns the arguments to run only the named test in a subprocess of the test binary.
+func isolatedArgs(testName string) []string {
+	levels := strings.Split(testName, "/")
+	for i, level := range levels {
+		levels[i] = "^" + regexp.QuoteMeta(level) + "$"
+	}
+
+	args := []string{"-test.run=" + strings.Join(levels, "/"), "-test.count=1", "-test.v=true"}
+	for _, name := range []string{"test.short", "test.timeout", "test.failfast"} {
+		if f := flag.Lookup(name); f != nil {
+			args = append(args, "-"+name+"="+f.Value.String())
+		}
+	}
+	return args
+}
@@ -0,0 +1,118 @@
+package quarantine_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestIsolate(t *testing.T) {
+	t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "true")
+
+	t.Run("exit", func(t *testing.T) {
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123", quarantine.Isolate())
+			os.Exit(3)
+		})
+
+		require.True(t, fake.Failed(), "test exiting in the subprocess should fail")
+		require.Equal(t, "fail", fake.Attrs()["isolated_outcome"])
+		require.Contains(t, fake.Logs(), "exit code 3")
+	})
+
+	t.Run("pass", func(t *testing.T) {
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123", quarantine.Isolate())
+			tb.Log("ran in subprocess")
+		})
+		if isIsolatedChild(t) {
+			return
+		}
+
+		require.False(t, fake.Failed(), "test passing in the subprocess should not fail")
+		require.Equal(t, "pass", fake.Attrs()["isolated_outcome"])
+		require.Contains(t, fake.Logs(), "--- PASS: TestIsolate/pass", "subprocess output should be streamed back")
+	})
+
+	t.Run("results file", func(t *testing.T) {
+		resultsFile := os.Getenv(quarantine.ResultsFileEnvVar)
+		if !isIsolatedChild(t) {
+			resultsFile = filepath.Join(t.TempDir(), "results.jsonl")
+			t.Setenv(quarantine.ResultsFileEnvVar, resultsFile)
+		}
+		runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123", quarantine.Isolate())
+		})
+		if isIsolatedChild(t) {
+			return
+		}
+
+		results := readResults(t, resultsFile)
+		require.Len(t, results, 1, "only the parent should record the isolated test")
+		require.Equal(t, "pass", results[0].Outcome, "the result should be the subprocess's outcome")
+		require.False(t, results[0].Skipped)
+	})
+
+	t.Run("report", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, quarantine.RunModeReport)
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123", quarantine.Isolate())
+			os.Exit(3)
+		})
+
+		require.True(t, fake.Failed(), "test exiting in the subprocess should fail")
+		require.Equal(t, "fail", fake.Attrs()["outcome"])
+		require.Contains(t, fake.Logs(), quarantine.ReportOnlyMarker)
+	})
+
+	t.Run("func pass", func(t *testing.T) {
+		ran := false
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.FlakyFunc(tb, "TEST-123", func(testing.TB) {
+				ran = true
+			}, quarantine.Isolate())
+		})
+		if isIsolatedChild(t) {
+			return
+		}
+
+		require.False(t, fake.Failed(), "test passing in the subprocess should not fail")
+		require.False(t, fake.Skipped(), "FlakyFunc passing in the subprocess should pass")
+		require.False(t, ran, "body should only run in the subprocess")
+		require.Equal(t, "pass", fake.Attrs()["isolated_outcome"])
+	})
+
+	t.Run("func report failure", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, quarantine.RunModeReport)
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.FlakyFunc(tb, "TEST-123", func(testing.TB) {
+				os.Exit(3)
+			}, quarantine.Isolate())
+		})
+
+		require.False(t, fake.Failed(), "failing subprocess should not fail the test in report mode")
+		require.Equal(t, "fail", fake.Attrs()["outcome"])
+		require.Contains(t, fake.Logs(), "exit code 3")
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv(quarantine.IsolateEnvVar, "true")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+			panic("boom")
+		})
+
+		require.True(t, fake.Failed(), "test panicking in the subprocess should fail")
+		require.Contains(t, fake.Logs(), "panic: boom")
+	})
+}
+
+// isIsolatedChild reports whether the test is running as the subprocess of an isolated test,
+// where it should run its body without asserting on the parent's view of the outcome.
+func isIsolatedChild(t *testing.T) bool {
+	return os.Getenv("QUARANTINE_ISOLATED_TEST") == t.Name()
+}
@@ -0,0 +1,34 @@
+package quarantine_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestKnownFailure(t *testing.T) {
+	t.Run("expected failure", func(t *testing.T) {
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.KnownFailure(tb, "TEST-123", func(tb testing.TB) {
+				tb.Fatal("division by zero")
+			})
+		})
+
+		require.True(t, fake.Skipped(), "expected failure should be reported as a skip")
+		require.False(t, fake.Failed(), "expected failure should not fail the test")
+		require.Contains(t, fake.Logs(), "division by zero")
+		require.Equal(t, "xfail", fake.Attrs()["known_failure_outcome"])
+	})
+
+	t.Run("unexpected pass", func(t *testing.T) {
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.KnownFailure(tb, "TEST-123", func(testing.TB) {})
+		})
+
+		require.True(t, fake.Failed(), "unexpected pass should fail the test")
+		require.Contains(t, fake.Logs(), "TEST-123 appears fixed — remove KnownFailure")
+		require.Equal(t, "xpass", fake.Attrs()["known_failure_outcome"])
+	})
+}
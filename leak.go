@@ -0,0 +1,129 @@
+package quarantine
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	// LeakCheckEnvVar is the environment variable that controls the goroutine leak check of quarantined tests
+	// that run. Leaks are reported by default, "strict" also fails the test, and "false" disables the check.
+	// Goroutines started by tests running in parallel can't be told apart from the quarantined test's own,
+	// so strict mode only fails tests when run with -test.parallel=1, and otherwise only reports leaks.
+	LeakCheckEnvVar = "QUARANTINE_LEAK_CHECK"
+
+	leakCheckStrict = "strict"
+	// leakCheckTimeout is how long goroutines get to exit after the test before they count as leaked.
+	leakCheckTimeout = 500 * time.Millisecond
+)
+
+// leakCheck compares the goroutines running before and after a test.
+type leakCheck struct {
+	before map[string]bool
+	strict bool
+}
+
+// startLeakCheck snapshots the running goroutines, or returns nil if the leak check is disabled.
+func startLeakCheck() *leakCheck {
+	mode := os.Getenv(LeakCheckEnvVar)
+	if mode == "false" {
+		return nil
+	}
+
+	before := map[string]bool{}
+	for _, g := range goroutines() {
+		before[g.id] = true
+	}
+	return &leakCheck{before: before, strict: mode == leakCheckStrict}
+}
+
+// serialTests reports whether tests run one at a time, so that goroutines started during a test are its own.
+func serialTests() bool {
+	f := flag.Lookup("test.parallel")
+	return f != nil && f.Value.String() == "1"
+}
+
+// report logs goroutines started during the test that are still running, and emits their count as the
+// leaked_goroutines attribute. In strict mode leaks also fail the test, if tests run one at a time.
+func (l *leakCheck) report(tb testing.TB) {
+	if l == nil {
+		return
+	}
+
+	var leaked []goroutine
+	for deadline := time.Now().Add(leakCheckTimeout); ; time.Sleep(10 * time.Millisecond) {
+		leaked = l.leaked()
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			break
+		}
+	}
+
+	attr(tb, "leaked_goroutines", strconv.Itoa(len(leaked)))
+	if len(leaked) == 0 {
+		return
+	}
+
+	stacks := make([]string, 0, len(leaked))
+	for _, g := range leaked {
+		stacks = append(stacks, g.stack)
+	}
+	msg := "Quarantined test leaked %d goroutine(s), which can make other tests flaky:\n\n%s"
+	fail := l.strict && serialTests()
+	if l.strict && !fail {
+		tb.Logf("%s=%s only fails tests run with -test.parallel=1, as leaks may belong to parallel tests.",
+			LeakCheckEnvVar, leakCheckStrict)
+	}
+	if fail {
+		tb.Errorf(msg, len(leaked), strings.Join(stacks, "\n\n"))
+	} else {
+		tb.Logf(msg, len(leaked), strings.Join(stacks, "\n\n"))
+	}
+}
+
+// leaked returns the goroutines that weren't running before the test, ignoring other tests.
+func (l *leakCheck) leaked() []goroutine {
+	var leaked []goroutine
+	for _, g := range goroutines() {
+		if l.before[g.id] || strings.Contains(g.stack, "testing.tRunner") {
+			continue
+		}
+		leaked = append(leaked, g)
+	}
+	sort.Slice(leaked, func(i, j int) bool {
+		return leaked[i].id < leaked[j].id
+	})
+	return leaked
+}
+
+// goroutine is a single goroutine from a stack dump.
+type goroutine struct {
+	id    string
+	stack string
+}
+
+// goroutines parses the stack dump of every goroutine, except the calling one.
+func goroutines() []goroutine {
+	var (
+		dump   = allStacks()
+		result []goroutine
+	)
+	for i, stack := range bytes.Split(bytes.TrimSpace(dump), []byte("\n\n")) {
+		if i == 0 {
+			// The first stack is always the calling goroutine
+			continue
+		}
+		header, _, _ := strings.Cut(string(stack), " [")
+		id, ok := strings.CutPrefix(header, "goroutine ")
+		if !ok {
+			continue
+		}
+		result = append(result, goroutine{id: id, stack: string(stack)})
+	}
+	return result
+}
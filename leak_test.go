@@ -0,0 +1,91 @@
+package quarantine_test
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestLeakCheck(t *testing.T) {
+	t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "true")
+
+	t.Run("report leak", func(t *testing.T) {
+		stop := make(chan struct{})
+		defer close(stop)
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+			go leakyWorker(stop)
+		})
+
+		require.False(t, fake.Failed(), "leaks should only be reported by default")
+		require.Equal(t, "1", fake.Attrs()["leaked_goroutines"])
+		require.Contains(t, fake.Logs(), "leakyWorker")
+	})
+
+	t.Run("fail leak in strict mode", func(t *testing.T) {
+		t.Setenv(quarantine.LeakCheckEnvVar, "strict")
+		setParallel(t, "1")
+		stop := make(chan struct{})
+		defer close(stop)
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+			go leakyWorker(stop)
+		})
+
+		require.True(t, fake.Failed(), "leaks should fail the test in strict mode")
+	})
+
+	t.Run("report leak in strict mode with parallel tests", func(t *testing.T) {
+		t.Setenv(quarantine.LeakCheckEnvVar, "strict")
+		setParallel(t, "4")
+		stop := make(chan struct{})
+		defer close(stop)
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+			go leakyWorker(stop)
+		})
+
+		require.False(t, fake.Failed(), "leaks may belong to parallel tests, so should only be reported")
+		require.Equal(t, "1", fake.Attrs()["leaked_goroutines"])
+	})
+
+	t.Run("no leak", func(t *testing.T) {
+		t.Setenv(quarantine.LeakCheckEnvVar, "strict")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+			stop := make(chan struct{})
+			go leakyWorker(stop)
+			tb.Cleanup(func() { close(stop) })
+		})
+
+		require.False(t, fake.Failed(), "goroutines stopped by the test's cleanups should not count as leaks")
+		require.Equal(t, "0", fake.Attrs()["leaked_goroutines"])
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv(quarantine.LeakCheckEnvVar, "false")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+
+		require.NotContains(t, fake.Attrs(), "leaked_goroutines")
+	})
+}
+
+func leakyWorker(stop <-chan struct{}) {
+	<-stop
+}
+
+// setParallel sets -test.parallel for the rest of the test. It doesn't change how tests are scheduled,
+// as the flag is only read when the test binary starts.
+func setParallel(t *testing.T, value string) {
+	t.Helper()
+
+	f := flag.Lookup("test.parallel")
+	original := f.Value.String()
+	require.NoError(t, f.Value.Set(value))
+	t.Cleanup(func() { _ = f.Value.Set(original) })
+}
@@ -0,0 +1,76 @@
+package quarantine
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// runMode is how a quarantined test is handled.
+type runMode int
+
+const (
+	modeSkip runMode = iota
+	modeRun
+	modeReport
+	modeSample
+	// modeIsolated is returned to quarantines that take the test body as a func once the body has already run
+	// in a subprocess.
+	modeIsolated
+)
+
+// resolveRunMode decides how a quarantined test is handled from the value of its run env var.
+// Besides "true", "false", and "report", the value can be a sampling rate between 0 and 1, see sampleTest,
+// or a comma-separated list of selectors:
+// ticket IDs, glob patterns on the full test name, or regular expressions wrapped in slashes.
+// Tests matched by any selector are run, all others are skipped.
+//
+//	RUN_QUARANTINED_TESTS=TEST-123,TestDatabase*,/^TestAPI/.*timeout$/
+func resolveRunMode(value, ticket, testName string) (runMode, error) {
+	switch value {
+	case "", "false":
+		return modeSkip, nil
+	case "true":
+		return modeRun, nil
+	case RunModeReport:
+		return modeReport, nil
+	}
+
+	if _, isRate, err := parseSampleRate(value); isRate {
+		return modeSample, err
+	}
+
+	for _, selector := range strings.Split(value, ",") {
+		matched, err := matchSelector(strings.TrimSpace(selector), ticket, testName)
+		if err != nil {
+			return modeSkip, err
+		}
+		if matched {
+			return modeRun, nil
+		}
+	}
+	return modeSkip, nil
+}
+
+// matchSelector reports whether a single selector matches the ticket or test name.
+func matchSelector(selector, ticket, testName string) (bool, error) {
+	switch {
+	case selector == "":
+		return false, nil
+	case selector == ticket:
+		return true, nil
+	case len(selector) > 1 && strings.HasPrefix(selector, "/") && strings.HasSuffix(selector, "/"):
+		re, err := regexp.Compile(selector[1 : len(selector)-1])
+		if err != nil {
+			return false, fmt.Errorf("invalid test name regex %q: %w", selector, err)
+		}
+		return re.MatchString(testName), nil
+	default:
+		matched, err := path.Match(selector, testName)
+		if err != nil {
+			return false, fmt.Errorf("invalid test name pattern %q: %w", selector, err)
+		}
+		return matched, nil
+	}
+}
@@ -0,0 +1,44 @@
+package quarantine_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestSelectiveEnablement(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected bool
+	}{
+		{name: "matching ticket", value: "TEST-1,TEST-123", expected: true},
+		{name: "other ticket", value: "TEST-1", expected: false},
+		{name: "matching glob", value: "TEST-1, TestSelectiveEnablement/*", expected: true},
+		{name: "other glob", value: "TestOther*", expected: false},
+		{name: "matching regex", value: "/regex$/", expected: true},
+		{name: "other regex", value: "/^TestOther/", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(quarantine.RunQuarantinedTestsEnvVar, test.value)
+			fake := runFake(t, func(tb testing.TB) {
+				quarantine.Flaky(tb, "TEST-123")
+			})
+
+			require.Equal(t, test.expected, !fake.Skipped(), "unexpected run decision for %q", test.value)
+		})
+	}
+
+	t.Run("invalid regex", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "/(/")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+
+		require.True(t, fake.Failed(), "invalid selector should fail the test")
+	})
+}
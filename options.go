@@ -0,0 +1,133 @@
+package quarantine
+
+import (
+	"testing"
+	"time"
+)
+
+// Option configures a quarantine.
+type Option func(*options)
+
+type options struct {
+	expires   time.Time
+	owner     string
+	reason    string
+	cause     RootCause
+	isolate   bool
+	serialize bool
+
+	signatures []string
+	conditions []condition
+	// funcBody is set by quarantines that take the test body as a func, which don't need to skip the test to keep
+	// an isolated body from running twice.
+	funcBody bool
+
+	// pkg is the import path of the calling package, for callers such as Run whose test runs on another stack.
+	pkg string
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Expires sets a date after which the quarantine is no longer honored. The quarantine still holds during that day.
+// Once expired, the test fails instead of being skipped, forcing someone to revisit the ticket.
+//
+// Example:
+//
+//	func TestFlaky(t *testing.T) {
+//		quarantine.Flaky(t, "TEST-123", quarantine.Expires(time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)))
+//	}
+func Expires(date time.Time) Option {
+	return func(o *options) {
+		o.expires = date
+	}
+}
+
+// Owner records the person or team responsible for fixing the test.
+func Owner(owner string) Option {
+	return func(o *options) {
+		o.owner = owner
+	}
+}
+
+// Reason records a short, single line description of why the test is quarantined.
+func Reason(reason string) Option {
+	return func(o *options) {
+		o.reason = reason
+	}
+}
+
+// Cause records the suspected root cause of the test's flakiness.
+func Cause(cause RootCause) Option {
+	return func(o *options) {
+		o.cause = cause
+	}
+}
+
+// RootCause categorizes why a test is flaky, so quarantines can be aggregated by cause.
+type RootCause string
+
+const (
+	// RootCauseRace is a data race or other concurrency bug.
+	RootCauseRace RootCause = "race"
+	// RootCauseTiming is a dependency on sleeps, deadlines, or wall clock time.
+	RootCauseTiming RootCause = "timing"
+	// RootCauseOrderDependence is a dependency on the order tests run in, or state left behind by other tests.
+	RootCauseOrderDependence RootCause = "order-dependence"
+	// RootCauseExternalResource is a dependency on a network service, container, or other external resource.
+	RootCauseExternalResource RootCause = "external-resource"
+	// RootCauseResourceExhaustion is running out of memory, file descriptors, ports, or disk.
+	RootCauseResourceExhaustion RootCause = "resource-exhaustion"
+)
+
+// callerPackage returns the import path of the package that quarantined the test.
+func (o *options) callerPackage() string {
+	if o.pkg != "" {
+		return o.pkg
+	}
+	return callerPackage()
+}
+
+// gatingOptions returns the names of the options that only apply to quarantines that decide whether the test runs.
+func (o *options) gatingOptions() []string {
+	var names []string
+	if !o.expires.IsZero() {
+		names = append(names, "Expires")
+	}
+	if o.isolate {
+		names = append(names, "Isolate")
+	}
+	if o.serialize {
+		names = append(names, "Serialize")
+	}
+	if len(o.conditions) > 0 {
+		names = append(names, "conditions")
+	}
+	return names
+}
+
+// emitAttrs emits an attribute for each piece of metadata that was set.
+func (o *options) emitAttrs(tb testing.TB) {
+	if o.owner != "" {
+		attr(tb, "owner", o.owner)
+	}
+	if o.reason != "" {
+		attr(tb, "reason", o.reason)
+	}
+	if o.cause != "" {
+		attr(tb, "root_cause", string(o.cause))
+	}
+}
+
+// expired reports whether the quarantine has lapsed, which happens at the end of the expiry date.
+func (o *options) expired(now time.Time) bool {
+	return !o.expires.IsZero() && !now.Before(o.expires.AddDate(0, 0, 1))
+}
+
+// expiryDateFormat is the format used to display and parse expiry dates.
+const expiryDateFormat = "2006-01-02"
@@ -3,9 +3,8 @@ package quarantine
 
 import (
 	"os"
-	"strings"
 	"testing"
-	"unicode"
+	"time"
 )
 
 const (
@@ -13,9 +12,22 @@ const (
 	RunQuarantinedTestsEnvVar = "RUN_QUARANTINED_TESTS"
 	// RunTimeoutTestsEnvVar is the environment variable that controls whether to run timeout tests.
 	RunTimeoutTestsEnvVar = "RUN_TIMEOUT_TESTS"
+
+	// RunModeReport is a value for the run env vars that runs quarantined tests in report-only mode.
+	// The test's outcome and duration are emitted as attributes, and a failure is marked with ReportOnlyMarker
+	// so that tooling such as junit-enhancer can keep it from failing the build.
+	RunModeReport = "report"
+	// ReportOnlyMarker is logged by failing tests that ran in report-only mode.
+	ReportOnlyMarker = "quarantine: report-only failure"
+
+	// RunOnlyQuarantinedTestsEnvVar is the environment variable for dedicated quarantined test jobs.
+	// When it is true, quarantined tests run unless their own env var says otherwise,
+	// Run skips subtests that aren't quarantined, and Main only runs the tests that use this package.
+	RunOnlyQuarantinedTestsEnvVar = "RUN_ONLY_QUARANTINED_TESTS"
 )
 
 // Flaky marks a test as flaky.
+// Options can attach extra metadata such as Owner, Reason, Cause, and Expires.
 // To run tests marked as flaky, set the RUN_FLAKY_TESTS environment variable to true.
 // To skip tests marked as flaky, set the RUN_FLAKY_TESTS environment variable to false (or don't set it at all).
 //
@@ -24,13 +36,60 @@ const (
 //	func TestFlaky(t *testing.T) {
 //		quarantine.Flaky(t, "TEST-123")
 //	}
-func Flaky(tb testing.TB, ticket string) {
+func Flaky(tb testing.TB, ticket string, opts ...Option) {
 	tb.Helper()
 
-	skipTest(tb, RunQuarantinedTestsEnvVar, "flaky", ticket)
+	skipTest(tb, FlakyClassification, ticket, newOptions(opts))
+}
+
+// FlakyFunc marks a test as flaky like Flaky, and when it runs, runs its body.
+// In report-only mode the body runs against a recorder, so a failure is logged and emitted as the outcome attribute
+// but doesn't fail the test, and go test still passes. Flaky can't do this, as the rest of the test body reports
+// its failures on the test directly, so failing report-only tests using Flaky rely on ReportOnlyMarker instead.
+// With the Isolate option, the body runs in a subprocess, and the test passes if the subprocess passes instead of
+// being skipped like with Flaky.
+//
+// Example:
+//
+//	func TestFlaky(t *testing.T) {
+//		quarantine.FlakyFunc(t, "TEST-123", func(tb testing.TB) {
+//			// Rest of test, using tb instead of t
+//		})
+//	}
+func FlakyFunc(tb testing.TB, ticket string, fn func(tb testing.TB), opts ...Option) {
+	tb.Helper()
+
+	o := newOptions(opts)
+	o.funcBody = true
+	mode := skipTest(tb, FlakyClassification, ticket, o)
+	if mode == modeIsolated {
+		return
+	}
+	if mode != modeReport {
+		fn(tb)
+		return
+	}
+
+	rec := record(tb, fn)
+	switch {
+	case rec.Skipped():
+		tb.Skip(rec.output())
+	case rec.Failed():
+		setBodyOutcome(tb, "fail")
+		tb.Logf(
+			"Test is marked as %s and failed in report-only mode, which doesn't fail the test:\n%s",
+			FlakyClassification.name,
+			rec.output(),
+		)
+	default:
+		if output := rec.output(); output != "" {
+			tb.Log(output)
+		}
+	}
 }
 
 // Timeout marks a test that is expected to timeout.
+// It accepts the same options as Flaky.
 // To run tests marked as timeout, set the RUN_TIMEOUT_TESTS environment variable to true.
 // To skip tests marked as timeout, set the RUN_TIMEOUT_TESTS environment variable to false (or don't set it at all).
 //
@@ -39,46 +98,150 @@ func Flaky(tb testing.TB, ticket string) {
 //	func TestTimeout(t *testing.T) {
 //		quarantine.Timeout(t, "TEST-123")
 //	}
-func Timeout(tb testing.TB, ticket string) {
+func Timeout(tb testing.TB, ticket string, opts ...Option) {
 	tb.Helper()
 
-	skipTest(tb, RunTimeoutTestsEnvVar, "timeout", ticket)
+	skipTest(tb, TimeoutClassification, ticket, newOptions(opts))
 }
 
-func skipTest(tb testing.TB, envVar, classification, ticket string) {
+// skipTest skips the quarantined test unless it is enabled, and returns how the enabled test runs.
+func skipTest(tb testing.TB, class *Classification, ticket string, opts *options) runMode {
 	tb.Helper()
 
+	classification, envVar := class.name, class.envVar
 	attr(tb, classification, ticket)
+	opts.emitAttrs(tb)
+	recordResult(tb, classification, ticket, opts.callerPackage())
+	recordSummary(tb, classification, ticket)
+	validateTicket(tb, ticket)
+	if len(opts.signatures) > 0 {
+		tb.Fatalf("The Signatures option only applies to Tolerate, the %s quarantine would ignore it", classification)
+		return modeSkip
+	}
+	if len(opts.conditions) > 0 {
+		tb.Fatalf("Conditions only apply to FlakyWhen, the %s quarantine would ignore them", classification)
+		return modeSkip
+	}
+	if url := ticketURL(ticket); url != "" {
+		attr(tb, "ticket_url", url)
+	}
+	if !opts.expires.IsZero() {
+		expires := opts.expires.Format(expiryDateFormat)
+		attr(tb, "expires", expires)
+		if opts.expired(time.Now()) {
+			tb.Fatalf(
+				"Quarantine for %s expired on %s. Fix the test or extend the quarantine.",
+				ticketRef(ticket),
+				expires,
+			)
+			return modeSkip
+		}
+	}
+
+	closed := checkClosedTicket(tb, ticket)
+
 	classifiedStr := "Classified by branch-out (https://github.com/smartcontractkit/branch-out)"
-	if os.Getenv(envVar) != "true" {
+	if class.skipInShort && testing.Short() && !closed {
+		tb.Skipf("Skipping '%s' test in short mode.\n%s", classification, classifiedStr)
+		return modeSkip
+	}
+
+	// Tests with closed tickets run regardless of the env var, to check whether the quarantine can be removed.
+	// The subprocess of an isolated test runs it too, as its parent already decided to.
+	mode := modeRun
+	if !closed && !isolatedChild(tb) {
+		value := os.Getenv(envVar)
+		if value == "" && onlyQuarantined() {
+			value = "true"
+		}
+		var err error
+		mode, err = resolveRunMode(value, ticket, tb.Name())
+		if err != nil {
+			tb.Fatalf("Invalid value for %s: %v", envVar, err)
+			return modeSkip
+		}
+
+		if mode == modeSample {
+			rate, _, _ := parseSampleRate(value)
+			sampled, seed := sampleTest(tb, rate)
+			if !sampled {
+				tb.Skipf(
+					"Test marked as '%s' was not sampled at rate %s. To reproduce, set %s='%s'.\n%s",
+					classification,
+					value,
+					SampleSeedEnvVar,
+					seed,
+					classifiedStr,
+				)
+				return modeSkip
+			}
+			mode = modeRun
+		}
+	}
+
+	switch mode {
+	case modeRun, modeReport:
+		charge, exhausted := checkBudget(tb)
+		if exhausted {
+			return modeSkip
+		}
+		acquireSlot(tb, opts)
+		charge.start(tb)
+		if mode == modeReport {
+			tb.Logf("Running test marked as '%s' in report-only mode.", classification)
+			reportOutcome(tb, classification)
+		}
+		if shouldIsolate(tb, opts) {
+			return isolate(tb, classification, mode, opts)
+		}
+		diag := startDiagnostics(tb)
+		leaks := startLeakCheck()
+		if mode == modeRun {
+			tb.Logf("Running test marked as '%s'.", classification)
+		}
+		tb.Cleanup(func() {
+			leaks.report(tb)
+			diag.capture(tb)
+			if mode == modeRun {
+				tb.Logf(
+					"Test is marked as %s, but still ran. To skip %s tests, set %s='false'.\n%s",
+					classification, classification, envVar, classifiedStr,
+				)
+			}
+		})
+	default:
 		tb.Skipf(
-			"To run '%s' tests, set %s='true'.\n%s",
+			"Test is marked as '%s', tracked by %s.\n"+
+				"To run '%s' tests, set %s='true' or to a comma-separated list of tickets and test name patterns.\n%s",
+			classification,
+			ticketRef(ticket),
 			classification,
 			envVar,
 			classifiedStr,
 		)
-	} else {
-		tb.Logf("Running test marked as '%s'.", classification)
-		tb.Cleanup(func() {
-			tb.Logf(
-				"Test is marked as %s, but still ran. To skip %s tests, set %s='false'.\n%s",
-				classification, classification, envVar, classifiedStr,
-			)
-		})
 	}
+	return mode
 }
 
-// attr replicates the functionality of testing.TB.Attr() for compatibility with older Go versions.
-// It emits a test attribute in the same format as the native Attr method.
-func attr(tb testing.TB, key, value string) {
-	if strings.ContainsFunc(key, unicode.IsSpace) {
-		tb.Errorf("disallowed whitespace in attribute key %q", key)
-		return
-	}
-	if strings.ContainsAny(value, "\r\n") {
-		tb.Errorf("disallowed newline in attribute value %q", value)
-		return
-	}
-	// Emit the attribute in the same format as testing.TB.Attr()
-	tb.Logf("=== ATTR  %s %s %s", tb.Name(), key, value)
+// onlyQuarantined reports whether only quarantined tests should run.
+func onlyQuarantined() bool {
+	return os.Getenv(RunOnlyQuarantinedTestsEnvVar) == "true"
+}
+
+// reportOutcome records the outcome and duration of a test running in report-only mode once it finishes.
+func reportOutcome(tb testing.TB, classification string) {
+	start := time.Now()
+	attr(tb, "quarantine_mode", RunModeReport)
+	tb.Cleanup(func() {
+		result := resultOutcome(tb)
+		if outcome(tb) == "fail" {
+			tb.Logf(
+				"%s: test is marked as %s and ran in report-only mode, this failure should not fail the build.",
+				ReportOnlyMarker,
+				classification,
+			)
+		}
+		attr(tb, "outcome", result)
+		attr(tb, "duration", time.Since(start).String())
+	})
 }
@@ -1,7 +1,14 @@
 package quarantine_test
 
 import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -49,3 +56,261 @@ func TestTimeout(t *testing.T) {
 		})
 	})
 }
+
+func TestOnlyQuarantined(t *testing.T) {
+	t.Run("run quarantined tests", func(t *testing.T) {
+		t.Setenv(quarantine.RunOnlyQuarantinedTestsEnvVar, "true")
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "")
+		quarantine.Flaky(t, "TEST-123")
+
+		t.Cleanup(func() {
+			require.False(t, t.Skipped(), "quarantined test should run when RUN_ONLY_QUARANTINED_TESTS is true")
+		})
+	})
+
+	t.Run("respect explicit env var", func(t *testing.T) {
+		t.Setenv(quarantine.RunOnlyQuarantinedTestsEnvVar, "true")
+		t.Setenv(quarantine.RunTimeoutTestsEnvVar, "false")
+		quarantine.Timeout(t, "TEST-123")
+
+		t.Cleanup(func() {
+			require.True(t, t.Skipped(), "timeout test should be skipped when RUN_TIMEOUT_TESTS is false")
+		})
+	})
+}
+
+func TestReportMode(t *testing.T) {
+	t.Run("report passing test", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, quarantine.RunModeReport)
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+
+		require.False(t, fake.Skipped(), "quarantined test should run in report mode")
+		require.Equal(t, "pass", fake.Attrs()["outcome"])
+		require.NotContains(t, fake.Logs(), quarantine.ReportOnlyMarker)
+	})
+
+	t.Run("report failing test", func(t *testing.T) {
+		t.Setenv(quarantine.RunTimeoutTestsEnvVar, quarantine.RunModeReport)
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Timeout(tb, "TEST-123")
+			tb.Error("still broken")
+		})
+
+		attrs := fake.Attrs()
+		require.Equal(t, quarantine.RunModeReport, attrs["quarantine_mode"])
+		require.Equal(t, "fail", attrs["outcome"])
+		require.Contains(t, attrs, "duration")
+		require.Contains(t, fake.Logs(), quarantine.ReportOnlyMarker)
+	})
+
+	t.Run("report failing FlakyFunc", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, quarantine.RunModeReport)
+		resultsFile := filepath.Join(t.TempDir(), "results.jsonl")
+		t.Setenv(quarantine.ResultsFileEnvVar, resultsFile)
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.FlakyFunc(tb, "TEST-123", func(tb testing.TB) {
+				tb.Fatal("still broken")
+			})
+		})
+
+		require.False(t, fake.Failed(), "failing body should not fail the test in report mode")
+		require.Equal(t, "fail", fake.Attrs()["outcome"])
+		require.Contains(t, fake.Logs(), "still broken")
+		require.NotContains(t, fake.Logs(), quarantine.ReportOnlyMarker)
+		results := readResults(t, resultsFile)
+		require.Len(t, results, 1)
+		require.Equal(t, "fail", results[0].Outcome, "results file should record the body's outcome")
+	})
+
+	t.Run("run failing FlakyFunc", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "true")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.FlakyFunc(tb, "TEST-123", func(tb testing.TB) {
+				tb.Error("still broken")
+			})
+		})
+
+		require.True(t, fake.Failed(), "failing body should fail the test outside report mode")
+	})
+}
+
+func TestExpires(t *testing.T) {
+	t.Run("fail expired quarantine", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123", quarantine.Expires(time.Now().AddDate(0, 0, -2)))
+		})
+
+		require.True(t, fake.Failed(), "expired quarantine should fail the test")
+		require.False(t, fake.Skipped(), "expired quarantine should not skip the test")
+		require.Contains(t, fake.Logs(), "Quarantine for TEST-123 expired on")
+	})
+
+	t.Run("skip quarantine on its expiry date", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123", quarantine.Expires(today))
+		})
+
+		require.False(t, fake.Failed(), "quarantine should hold until the end of its expiry date")
+		require.True(t, fake.Skipped())
+	})
+
+	t.Run("skip unexpired quarantine", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123", quarantine.Expires(time.Now().AddDate(0, 0, 1)))
+		})
+
+		require.False(t, fake.Failed(), "unexpired quarantine should not fail the test")
+		require.True(t, fake.Skipped(), "unexpired quarantine should skip the test")
+		require.Contains(t, fake.Attrs(), "expires")
+	})
+}
+
+func TestMetadata(t *testing.T) {
+	t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+	fake := runFake(t, func(tb testing.TB) {
+		quarantine.Flaky(
+			tb,
+			"TEST-123",
+			quarantine.Owner("team-a"),
+			quarantine.Reason("fails when the port is taken"),
+			quarantine.Cause(quarantine.RootCauseResourceExhaustion),
+		)
+	})
+
+	require.True(t, fake.Skipped(), "quarantined test should be skipped")
+	attrs := fake.Attrs()
+	require.Equal(t, "team-a", attrs["owner"])
+	require.Equal(t, "fails when the port is taken", attrs["reason"])
+	require.Equal(t, "resource-exhaustion", attrs["root_cause"])
+}
+
+// fakeTB records failures, skips, logs, and attributes instead of reporting them on the real test.
+type fakeTB struct {
+	testing.TB
+
+	name     string
+	mu       sync.Mutex
+	failed   bool
+	skipped  bool
+	output   []string
+	attrs    map[string]string
+	cleanups []func()
+}
+
+// runFake runs fn against a fakeTB wrapping t, and returns it once fn and its cleanups have finished.
+func runFake(t *testing.T, fn func(tb testing.TB)) *fakeTB {
+	t.Helper()
+
+	return runNamedFake(t, "", fn)
+}
+
+// runNamedFake is like runFake, but the fakeTB reports name as its test name, so fakes running concurrently
+// are told apart like real tests. An empty name uses t's name.
+func runNamedFake(t *testing.T, name string, fn func(tb testing.TB)) *fakeTB {
+	t.Helper()
+
+	fake := &fakeTB{TB: t, name: name, attrs: map[string]string{}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer fake.runCleanups()
+		fn(fake)
+	}()
+	<-done
+	return fake
+}
+
+func (f *fakeTB) Name() string {
+	if f.name != "" {
+		return f.name
+	}
+	return f.TB.Name()
+}
+
+func (f *fakeTB) runCleanups() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func (f *fakeTB) Logs() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return fmt.Sprint(f.output)
+}
+
+// Attrs returns the attributes emitted on the test, either natively or through the log line shim.
+func (f *fakeTB) Attrs() map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return maps.Clone(f.attrs)
+}
+
+func (f *fakeTB) Attr(key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attrs[key] = value
+}
+
+func (f *fakeTB) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+func (f *fakeTB) Helper()           {}
+
+func (f *fakeTB) Log(args ...any) {
+	line := fmt.Sprintln(args...)
+	if attr, ok := strings.CutPrefix(line, "=== ATTR  "+f.Name()+" "); ok {
+		key, value, _ := strings.Cut(strings.TrimSuffix(attr, "\n"), " ")
+		f.Attr(key, value)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.output = append(f.output, line)
+}
+
+func (f *fakeTB) Logf(format string, args ...any) { f.Log(fmt.Sprintf(format, args...)) }
+
+func (f *fakeTB) Fail() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failed = true
+}
+
+func (f *fakeTB) Failed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.failed
+}
+
+func (f *fakeTB) FailNow() {
+	f.Fail()
+	runtime.Goexit()
+}
+
+func (f *fakeTB) Error(args ...any)                 { f.Log(args...); f.Fail() }
+func (f *fakeTB) Errorf(format string, args ...any) { f.Logf(format, args...); f.Fail() }
+func (f *fakeTB) Fatal(args ...any)                 { f.Log(args...); f.FailNow() }
+func (f *fakeTB) Fatalf(format string, args ...any) { f.Logf(format, args...); f.FailNow() }
+
+func (f *fakeTB) SkipNow() {
+	f.mu.Lock()
+	f.skipped = true
+	f.mu.Unlock()
+	runtime.Goexit()
+}
+
+func (f *fakeTB) Skipped() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.skipped
+}
+
+func (f *fakeTB) Skip(args ...any)                 { f.Log(args...); f.SkipNow() }
+func (f *fakeTB) Skipf(format string, args ...any) { f.Logf(format, args...); f.SkipNow() }
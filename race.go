@@ -0,0 +1,6 @@
+//go:build race
+
+package quarantine
+
+// raceEnabled reports whether the test binary was built with the race detector.
+const raceEnabled = true
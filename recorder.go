@@ -0,0 +1,202 @@
+package quarantine
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recorder is a testing.TB that captures the failures, skips, and logs of a test body
+// instead of reporting them on the real test it wraps.
+type recorder struct {
+	testing.TB
+
+	mu       sync.Mutex
+	failed   bool
+	skipped  bool
+	logs     []string
+	failures []string
+	cleanups []func()
+	// abandoned is set once the real test stopped waiting for the body, which may have already finished.
+	abandoned bool
+}
+
+// record runs fn against a recorder wrapping tb and returns the recorder once fn and its cleanups have finished.
+// fn runs on its own goroutine so that FailNow and SkipNow only stop the body, not the real test.
+func record(tb testing.TB, fn func(tb testing.TB)) *recorder {
+	rec, done := startRecording(tb, fn)
+	<-done
+	return rec
+}
+
+// startRecording is like record, but returns immediately along with a channel that is closed once fn finishes.
+func startRecording(tb testing.TB, fn func(tb testing.TB)) (*recorder, <-chan struct{}) {
+	rec := &recorder{TB: tb}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer rec.runCleanups()
+		defer func() {
+			if r := recover(); r != nil {
+				rec.Errorf("panic: %v\n%s", r, debug.Stack())
+			}
+		}()
+		fn(rec)
+	}()
+	return rec, done
+}
+
+func (r *recorder) runCleanups() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+// output returns everything the body logged, one entry per line.
+func (r *recorder) output() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.logs, "\n")
+}
+
+// failureMessages returns the messages passed to Error, Errorf, Fatal, and Fatalf, in the order they were reported.
+func (r *recorder) failureMessages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.failures...)
+}
+
+// addFailure records a failure message, it is also logged like any other output.
+func (r *recorder) addFailure(msg string) {
+	r.Log(msg)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures = append(r.failures, msg)
+}
+
+// abandon stops passing calls through to the real test, for a body that is left running after it.
+func (r *recorder) abandon() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.abandoned = true
+}
+
+func (r *recorder) isAbandoned() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.abandoned
+}
+
+func (r *recorder) Helper() {}
+
+// Attr emits the attribute on the real test, or drops it once the body is abandoned.
+func (r *recorder) Attr(key, value string) {
+	if r.isAbandoned() {
+		return
+	}
+	attr(r.TB, key, value)
+}
+
+// TempDir returns a directory removed once the body finishes. It belongs to the real test unless the body is
+// abandoned, in which case the real test's cleanups may have already run.
+func (r *recorder) TempDir() string {
+	if !r.isAbandoned() {
+		return r.TB.TempDir()
+	}
+
+	dir, err := os.MkdirTemp("", "quarantine-abandoned-")
+	if err != nil {
+		r.Fatalf("TempDir: %v", err)
+	}
+	r.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return dir
+}
+
+// Setenv sets the variable for the real test, or does nothing once the body is abandoned, as the real test has
+// already restored its environment and the variable would leak into other tests.
+func (r *recorder) Setenv(key, value string) {
+	if r.isAbandoned() {
+		return
+	}
+	r.TB.Setenv(key, value)
+}
+
+func (r *recorder) Cleanup(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cleanups = append(r.cleanups, fn)
+}
+
+func (r *recorder) Log(args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs = append(r.logs, strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+func (r *recorder) Logf(format string, args ...any) {
+	r.Log(fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fail() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed = true
+}
+
+func (r *recorder) Failed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.failed
+}
+
+func (r *recorder) FailNow() {
+	r.Fail()
+	runtime.Goexit()
+}
+
+func (r *recorder) Error(args ...any) {
+	r.addFailure(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+	r.Fail()
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.addFailure(fmt.Sprintf(format, args...))
+	r.Fail()
+}
+
+func (r *recorder) Fatal(args ...any) {
+	r.addFailure(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+	r.FailNow()
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.addFailure(fmt.Sprintf(format, args...))
+	r.FailNow()
+}
+
+func (r *recorder) SkipNow() {
+	r.mu.Lock()
+	r.skipped = true
+	r.mu.Unlock()
+	runtime.Goexit()
+}
+
+func (r *recorder) Skipped() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.skipped
+}
+
+func (r *recorder) Skip(args ...any) {
+	r.Log(args...)
+	r.SkipNow()
+}
+
+func (r *recorder) Skipf(format string, args ...any) {
+	r.Logf(format, args...)
+	r.SkipNow()
+}
@@ -0,0 +1,231 @@
+package quarantine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RegistryFileEnvVar is the environment variable that points to a quarantine registry file.
+const RegistryFileEnvVar = "QUARANTINE_REGISTRY_FILE"
+
+// RegistryEntry quarantines every test matching Package and Test.
+type RegistryEntry struct {
+	// Package is the import path of the test's package, glob patterns are allowed.
+	// An empty package matches every package.
+	Package string `json:"package,omitempty" yaml:"package,omitempty"`
+	// Test is a glob pattern matched against the full test name, e.g. "TestFoo" or "TestFoo/*".
+	Test string `json:"test" yaml:"test"`
+	// Classification is the name of a registered classification, e.g. "flaky" or "timeout".
+	Classification string `json:"classification" yaml:"classification"`
+	// Ticket is the ticket tracking the quarantine.
+	Ticket string `json:"ticket" yaml:"ticket"`
+	// Expires is an optional expiry date in YYYY-MM-DD format, see Expires.
+	Expires string `json:"expires,omitempty" yaml:"expires,omitempty"`
+	// Owner is the optional person or team responsible for the test, see Owner.
+	Owner string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	// Reason is an optional description of the quarantine, see Reason.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	// RootCause is the optional suspected root cause, see Cause.
+	RootCause RootCause `json:"root_cause,omitempty" yaml:"root_cause,omitempty"`
+}
+
+var (
+	registryFiles     = newFileCache(parseRegistry)
+	yamlRegistryFiles = newFileCache(parseYAMLRegistry)
+)
+
+// Check looks up the running test in the registry file pointed to by QUARANTINE_REGISTRY_FILE,
+// and applies the same gating as Classify if the test is listed.
+// Tests that are not listed, or runs without a registry file, are left untouched.
+// This lets tests be quarantined by committing a single data file instead of editing the test itself.
+//
+// The registry is a JSON array of entries:
+//
+//	[
+//		{"package": "github.com/org/repo/pkg", "test": "TestFlaky", "classification": "flaky", "ticket": "TEST-123"},
+//		{"test": "TestSlow/*", "classification": "timeout", "ticket": "TEST-456", "expires": "2025-12-31"}
+//	]
+//
+// Files ending in .yaml or .yml hold the same entries as a YAML sequence.
+//
+// Example:
+//
+//	func TestSomething(t *testing.T) {
+//		quarantine.Check(t)
+//	}
+func Check(tb testing.TB) {
+	tb.Helper()
+
+	registryFile := os.Getenv(RegistryFileEnvVar)
+	if registryFile == "" {
+		return
+	}
+
+	entries, err := loadRegistry(registryFile)
+	if err != nil {
+		tb.Fatalf("Failed to load quarantine registry %s: %v", registryFile, err)
+		return
+	}
+
+	entry, found := matchRegistry(entries, callerPackage(), tb.Name())
+	if !found {
+		return
+	}
+
+	class, ok := lookupClassification(entry.Classification)
+	if !ok {
+		tb.Fatalf(
+			"Unknown classification '%s' for %s in quarantine registry %s",
+			entry.Classification,
+			tb.Name(),
+			registryFile,
+		)
+		return
+	}
+	skipTest(tb, class, entry.Ticket, newOptions(entry.options()))
+}
+
+// options converts the entry's metadata into quarantine options.
+func (e RegistryEntry) options() []Option {
+	opts := []Option{Owner(e.Owner), Reason(e.Reason), Cause(e.RootCause)}
+	if e.Expires != "" {
+		expires, _ := time.Parse(expiryDateFormat, e.Expires) // validated in validateRegistry
+		opts = append(opts, Expires(expires))
+	}
+	return opts
+}
+
+// loadRegistry returns the entries of the registry file, parsed as YAML if its name ends in .yaml or .yml,
+// and as JSON otherwise.
+func loadRegistry(registryFile string) ([]RegistryEntry, error) {
+	switch strings.ToLower(filepath.Ext(registryFile)) {
+	case ".yaml", ".yml":
+		return yamlRegistryFiles.load(registryFile)
+	default:
+		return registryFiles.load(registryFile)
+	}
+}
+
+// parseRegistry parses and validates the entries of a JSON registry file.
+func parseRegistry(data []byte) ([]RegistryEntry, error) {
+	var entries []RegistryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	if err := validateRegistry(entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// parseYAMLRegistry parses and validates the entries of a YAML registry file.
+func parseYAMLRegistry(data []byte) ([]RegistryEntry, error) {
+	var entries []RegistryEntry
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	if err := validateRegistry(entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// validateRegistry checks the patterns and expiry dates of the entries.
+func validateRegistry(entries []RegistryEntry) error {
+	for i, entry := range entries {
+		if entry.Test == "" {
+			return fmt.Errorf("entry %d has no test pattern", i)
+		}
+		if _, err := path.Match(entry.Test, ""); err != nil {
+			return fmt.Errorf("entry %d has invalid test pattern %q: %w", i, entry.Test, err)
+		}
+		if _, err := path.Match(entry.Package, ""); err != nil {
+			return fmt.Errorf("entry %d has invalid package pattern %q: %w", i, entry.Package, err)
+		}
+		if entry.Expires != "" {
+			if _, err := time.Parse(expiryDateFormat, entry.Expires); err != nil {
+				return fmt.Errorf("entry %d has invalid expiry date %q: %w", i, entry.Expires, err)
+			}
+		}
+	}
+	return nil
+}
+
+// matchRegistry returns the first entry matching the package and test name.
+func matchRegistry(entries []RegistryEntry, pkg, testName string) (RegistryEntry, bool) {
+	for _, entry := range entries {
+		if entry.Package != "" {
+			if ok, _ := path.Match(entry.Package, pkg); !ok {
+				continue
+			}
+		}
+		if ok, _ := path.Match(entry.Test, testName); ok {
+			return entry, true
+		}
+	}
+	return RegistryEntry{}, false
+}
+
+// registryMayMatch reports whether any entry could match the top-level test or one of its subtests.
+func registryMayMatch(entries []RegistryEntry, pkg, testName string) bool {
+	for _, entry := range entries {
+		if entry.Package != "" {
+			if ok, _ := path.Match(entry.Package, pkg); !ok {
+				continue
+			}
+		}
+		topLevel, _, _ := strings.Cut(entry.Test, "/")
+		if ok, _ := path.Match(topLevel, testName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// callerPackage returns the import path of the package that called into the quarantine package.
+// External test packages (pkg_test) are reported as the package they test.
+func callerPackage() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		pkg := funcPackage(frame.Function)
+		if pkg != "" && pkg != thisPackage {
+			return strings.TrimSuffix(pkg, "_test")
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// thisPackage is the import path of this package, used to skip our own frames when finding the caller.
+const thisPackage = "github.com/smartcontractkit/quarantine"
+
+// funcPackage extracts the package import path from a fully qualified function name,
+// e.g. "github.com/org/repo/pkg.TestFoo.func1" -> "github.com/org/repo/pkg".
+// The linker escapes dots in the last element of the path, so "gopkg.in/yaml.v3" appears as "gopkg.in/yaml%2ev3",
+// and the first dot after the last slash always ends the path.
+func funcPackage(funcName string) string {
+	lastSlash := strings.LastIndex(funcName, "/")
+	dot := strings.Index(funcName[lastSlash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+	pkg := funcName[:lastSlash+1+dot]
+	if unescaped, err := url.PathUnescape(pkg); err == nil {
+		return unescaped
+	}
+	return pkg
+}
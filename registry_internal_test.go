@@ -0,0 +1,23 @@
+package quarantine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuncPackage(t *testing.T) {
+	tests := map[string]string{
+		"github.com/org/repo/pkg.TestFoo":          "github.com/org/repo/pkg",
+		"github.com/org/repo/pkg.TestFoo.func1":    "github.com/org/repo/pkg",
+		"github.com/org/repo/pkg.(*suite).TestFoo": "github.com/org/repo/pkg",
+		"github.com/org/repo/pkg_test.TestFoo":     "github.com/org/repo/pkg_test",
+		"gopkg.in/yaml%2ev3.TestFoo.func1":         "gopkg.in/yaml.v3",
+		"github.com/org/repo/v2/x%2ev3.TestFoo":    "github.com/org/repo/v2/x.v3",
+		"main.main":                                "main",
+		"noDot":                                    "",
+	}
+	for funcName, want := range tests {
+		require.Equal(t, want, funcPackage(funcName), funcName)
+	}
+}
@@ -0,0 +1,85 @@
+package quarantine_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func writeRegistry(t *testing.T, contents string) {
+	t.Helper()
+
+	registryFile := filepath.Join(t.TempDir(), "quarantine.json")
+	require.NoError(t, os.WriteFile(registryFile, []byte(contents), 0600))
+	t.Setenv(quarantine.RegistryFileEnvVar, registryFile)
+}
+
+func TestCheck(t *testing.T) {
+	t.Run("skip registered test", func(t *testing.T) {
+		writeRegistry(t, `[
+			{"package": "github.com/smartcontractkit/quarantine", "test": "TestCheck/skip_*", "classification": "flaky", "ticket": "TEST-123"}
+		]`)
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		quarantine.Check(t)
+
+		t.Cleanup(func() {
+			require.True(t, t.Skipped(), "registered test should be skipped when RUN_QUARANTINED_TESTS is false")
+		})
+	})
+
+	t.Run("run registered test", func(t *testing.T) {
+		writeRegistry(t, `[{"test": "TestCheck/run_registered_test", "classification": "timeout", "ticket": "TEST-123"}]`)
+		t.Setenv(quarantine.RunTimeoutTestsEnvVar, "true")
+		quarantine.Check(t)
+
+		t.Cleanup(func() {
+			require.False(t, t.Skipped(), "registered test should not be skipped when RUN_TIMEOUT_TESTS is true")
+		})
+	})
+
+	t.Run("skip test registered in YAML", func(t *testing.T) {
+		registryFile := filepath.Join(t.TempDir(), "quarantine.yaml")
+		contents := `
+- package: github.com/smartcontractkit/quarantine
+  test: TestCheck/skip_test_registered_in_YAML
+  classification: flaky
+  ticket: TEST-123
+  owner: team-a
+`
+		require.NoError(t, os.WriteFile(registryFile, []byte(contents), 0600))
+		t.Setenv(quarantine.RegistryFileEnvVar, registryFile)
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Check(tb)
+		})
+
+		require.True(t, fake.Skipped(), "test registered in a YAML registry should be skipped")
+		require.Equal(t, "team-a", fake.Attrs()["owner"])
+	})
+
+	t.Run("ignore other packages", func(t *testing.T) {
+		writeRegistry(t, `[
+			{"package": "github.com/smartcontractkit/other", "test": "TestCheck/*", "classification": "flaky", "ticket": "TEST-123"}
+		]`)
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		quarantine.Check(t)
+
+		t.Cleanup(func() {
+			require.False(t, t.Skipped(), "test from another package should not be skipped")
+		})
+	})
+
+	t.Run("no registry", func(t *testing.T) {
+		t.Setenv(quarantine.RegistryFileEnvVar, "")
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		quarantine.Check(t)
+
+		t.Cleanup(func() {
+			require.False(t, t.Skipped(), "test should not be skipped without a registry")
+		})
+	})
+}
@@ -0,0 +1,105 @@
+package quarantine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ResultsFileEnvVar is the environment variable that points to a file quarantined test results are appended to.
+const ResultsFileEnvVar = "QUARANTINE_RESULTS_FILE"
+
+// Result is a single line of the results file, written once a quarantined test finishes.
+type Result struct {
+	Package         string    `json:"package"`
+	Test            string    `json:"test"`
+	Classification  string    `json:"classification"`
+	Ticket          string    `json:"ticket"`
+	Skipped         bool      `json:"skipped"`
+	Outcome         string    `json:"outcome"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Time            time.Time `json:"time"`
+}
+
+// recordResult appends the result of the test to the results file once it finishes, if one is configured.
+// The file is locked while writing, so package test binaries running in parallel can share it.
+func recordResult(tb testing.TB, classification, ticket, pkg string) {
+	resultsFile := os.Getenv(ResultsFileEnvVar)
+	if resultsFile == "" || isolatedChild(tb) {
+		return
+	}
+
+	result := Result{
+		Package:        pkg,
+		Test:           tb.Name(),
+		Classification: classification,
+		Ticket:         ticket,
+		Time:           time.Now(),
+	}
+	tb.Cleanup(func() {
+		result.Outcome = resultOutcome(tb)
+		result.Skipped = result.Outcome == "skip"
+		result.DurationSeconds = time.Since(result.Time).Seconds()
+		if err := appendResult(resultsFile, result); err != nil {
+			tb.Errorf("Failed to write quarantine result to %s: %v", resultsFile, err)
+		}
+	})
+}
+
+// outcome describes how a finished test ended: pass, fail, or skip.
+func outcome(tb testing.TB) string {
+	switch {
+	case tb.Skipped():
+		return "skip"
+	case tb.Failed():
+		return "fail"
+	default:
+		return "pass"
+	}
+}
+
+// bodyOutcomes maps the names of tests whose body ran outside the test itself, in a subprocess or against a
+// recorder, to the outcome of the body.
+var bodyOutcomes sync.Map
+
+// setBodyOutcome records the outcome of a test body that ran outside the test, which the results file and Main's
+// summary report instead of the test's own outcome.
+func setBodyOutcome(tb testing.TB, result string) {
+	bodyOutcomes.Store(tb.Name(), result)
+}
+
+// resultOutcome is the outcome recorded for a finished test. Tests whose body ran outside the test report the
+// body's outcome, as the test itself is skipped once an isolated subprocess passes, and passes when a body
+// running in report-only mode fails.
+func resultOutcome(tb testing.TB) string {
+	if result, ok := bodyOutcomes.Load(tb.Name()); ok {
+		return result.(string)
+	}
+	return outcome(tb)
+}
+
+func appendResult(resultsFile string, result Result) error {
+	line, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	// #nosec G304 - path is provided by the user running the tests
+	f, err := os.OpenFile(resultsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := lockFile(f); err != nil {
+		return fmt.Errorf("failed to lock: %w", err)
+	}
+	defer func() { _ = unlockFile(f) }()
+
+	_, err = f.Write(line)
+	return err
+}
@@ -0,0 +1,76 @@
+package quarantine_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestResultsFile(t *testing.T) {
+	resultsFile := filepath.Join(t.TempDir(), "results.jsonl")
+	t.Setenv(quarantine.ResultsFileEnvVar, resultsFile)
+
+	t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+	runFake(t, func(tb testing.TB) {
+		quarantine.Flaky(tb, "TEST-1")
+	})
+
+	t.Setenv(quarantine.RunTimeoutTestsEnvVar, "true")
+	runFake(t, func(tb testing.TB) {
+		quarantine.Timeout(tb, "TEST-2")
+		tb.Error("still broken")
+	})
+
+	results := readResults(t, resultsFile)
+	require.Len(t, results, 2)
+
+	require.Equal(t, "github.com/smartcontractkit/quarantine", results[0].Package)
+	require.Equal(t, "TestResultsFile", results[0].Test)
+	require.Equal(t, "flaky", results[0].Classification)
+	require.Equal(t, "TEST-1", results[0].Ticket)
+	require.True(t, results[0].Skipped)
+	require.Equal(t, "skip", results[0].Outcome)
+
+	require.Equal(t, "timeout", results[1].Classification)
+	require.Equal(t, "TEST-2", results[1].Ticket)
+	require.False(t, results[1].Skipped)
+	require.Equal(t, "fail", results[1].Outcome)
+}
+
+func TestResultsFileSubtest(t *testing.T) {
+	resultsFile := filepath.Join(t.TempDir(), "results.jsonl")
+	t.Setenv(quarantine.ResultsFileEnvVar, resultsFile)
+	t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+
+	quarantine.Run(t, "row", "TEST-1", func(*testing.T) {})
+
+	results := readResults(t, resultsFile)
+	require.Len(t, results, 1)
+	require.Equal(t, "github.com/smartcontractkit/quarantine", results[0].Package, "package should be the caller of Run")
+	require.Equal(t, "TestResultsFileSubtest/row", results[0].Test)
+	require.True(t, results[0].Skipped)
+}
+
+func readResults(t *testing.T, resultsFile string) []quarantine.Result {
+	t.Helper()
+
+	f, err := os.Open(resultsFile)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var results []quarantine.Result
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var result quarantine.Result
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &result))
+		results = append(results, result)
+	}
+	require.NoError(t, scanner.Err())
+	return results
+}
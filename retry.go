@@ -0,0 +1,61 @@
+package quarantine
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Retry runs the body of a flaky test up to attempts times, stopping at the first attempt that passes.
+// Each attempt runs against its own recorder, so failures of earlier attempts are logged but don't fail the test.
+// The test only fails if every attempt fails. Unlike Flaky, Retry always runs the body.
+// The number of attempts run and which of them failed are emitted as attributes.
+//
+// Example:
+//
+//	func TestFlaky(t *testing.T) {
+//		quarantine.Retry(t, "TEST-123", 3, func(tb testing.TB) {
+//			// Rest of test
+//		})
+//	}
+func Retry(tb testing.TB, ticket string, attempts int, fn func(tb testing.TB)) {
+	tb.Helper()
+
+	attr(tb, FlakyClassification.name, ticket)
+	validateTicket(tb, ticket)
+	attempts = max(attempts, 1)
+
+	var (
+		failedAttempts []string
+		rec            *recorder
+		attempt        int
+	)
+	for attempt = 1; attempt <= attempts; attempt++ {
+		rec = record(tb, fn)
+		if !rec.Failed() || rec.Skipped() {
+			break
+		}
+		failedAttempts = append(failedAttempts, strconv.Itoa(attempt))
+		tb.Logf("Attempt %d/%d failed:\n%s", attempt, attempts, rec.output())
+	}
+	attempt = min(attempt, attempts)
+
+	attr(tb, "attempts", strconv.Itoa(attempt))
+	if len(failedAttempts) > 0 {
+		attr(tb, "failed_attempts", strings.Join(failedAttempts, ","))
+	}
+
+	switch {
+	case rec.Skipped():
+		tb.Skip(rec.output())
+	case rec.Failed():
+		tb.Errorf("All %d attempts failed for flaky test tracked by %s", attempts, ticketRef(ticket))
+	default:
+		if output := rec.output(); output != "" {
+			tb.Log(output)
+		}
+		if len(failedAttempts) > 0 {
+			tb.Logf("Passed on attempt %d/%d", attempt, attempts)
+		}
+	}
+}
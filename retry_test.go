@@ -0,0 +1,65 @@
+package quarantine_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestRetry(t *testing.T) {
+	t.Run("pass after failed attempts", func(t *testing.T) {
+		runs := 0
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Retry(tb, "TEST-123", 3, func(tb testing.TB) {
+				runs++
+				if runs < 3 {
+					tb.Fatalf("attempt %d failed", runs)
+				}
+			})
+		})
+
+		require.Equal(t, 3, runs, "body should run until it passes")
+		require.False(t, fake.Failed(), "test should pass when an attempt passes")
+		attrs := fake.Attrs()
+		require.Equal(t, "3", attrs["attempts"])
+		require.Equal(t, "1,2", attrs["failed_attempts"])
+		require.Contains(t, fake.Logs(), "attempt 2 failed")
+	})
+
+	t.Run("pass first attempt", func(t *testing.T) {
+		runs := 0
+		quarantine.Retry(t, "TEST-123", 3, func(testing.TB) {
+			runs++
+		})
+
+		require.Equal(t, 1, runs, "body should not be retried once it passes")
+	})
+
+	t.Run("fail all attempts", func(t *testing.T) {
+		runs := 0
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Retry(tb, "TEST-123", 2, func(tb testing.TB) {
+				runs++
+				tb.Error("always fails")
+			})
+		})
+
+		require.Equal(t, 2, runs, "body should run for every attempt")
+		require.True(t, fake.Failed(), "test should fail when every attempt fails")
+		require.Contains(t, fake.Logs(), "All 2 attempts failed")
+	})
+
+	t.Run("recover panics", func(t *testing.T) {
+		runs := 0
+		quarantine.Retry(t, "TEST-123", 2, func(testing.TB) {
+			runs++
+			if runs == 1 {
+				panic("boom")
+			}
+		})
+
+		require.Equal(t, 2, runs, "panicking attempt should be retried")
+	})
+}
@@ -0,0 +1,59 @@
+package quarantine
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// SampleSeedEnvVar is the environment variable that seeds the sampling of quarantined tests.
+// When unset, a seed is picked once per test binary. Either way the seed is emitted as an attribute,
+// so setting it to the same value reproduces which tests were sampled.
+const SampleSeedEnvVar = "QUARANTINE_SAMPLE_SEED"
+
+// defaultSampleSeed is used when SampleSeedEnvVar is unset.
+var defaultSampleSeed = sync.OnceValue(func() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 10)
+})
+
+// parseSampleRate parses a run env var value as a sampling rate between 0 and 1.
+// ok is false if the value is not a number at all.
+func parseSampleRate(value string) (rate float64, ok bool, err error) {
+	rate, err = strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false, nil
+	}
+	if math.IsNaN(rate) || rate < 0 || rate > 1 {
+		return 0, true, fmt.Errorf("sample rate %s must be between 0 and 1", value)
+	}
+	return rate, true, nil
+}
+
+// sampleTest decides whether a quarantined test runs when its env var holds a sampling rate.
+// The decision is a deterministic function of the seed and the test name, and is emitted as attributes.
+func sampleTest(tb testing.TB, rate float64) (sampled bool, seed string) {
+	seed = os.Getenv(SampleSeedEnvVar)
+	if seed == "" {
+		seed = defaultSampleSeed()
+	}
+	sampled = sampleValue(seed, tb.Name()) < rate
+
+	attr(tb, "sample_rate", strconv.FormatFloat(rate, 'g', -1, 64))
+	attr(tb, "sample_seed", seed)
+	attr(tb, "sampled", strconv.FormatBool(sampled))
+	return sampled, seed
+}
+
+// sampleValue maps a seed and test name to a value uniformly distributed in [0, 1).
+func sampleValue(seed, testName string) float64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(seed))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(testName))
+	return float64(h.Sum64()>>11) / (1 << 53)
+}
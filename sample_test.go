@@ -0,0 +1,58 @@
+package quarantine_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestSampling(t *testing.T) {
+	t.Run("never sample", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "0")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+
+		require.True(t, fake.Skipped(), "test should be skipped at rate 0")
+		require.Equal(t, "false", fake.Attrs()["sampled"])
+	})
+
+	t.Run("always sample", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "1.0")
+		t.Setenv(quarantine.SampleSeedEnvVar, "42")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+
+		require.False(t, fake.Skipped(), "test should run at rate 1")
+		attrs := fake.Attrs()
+		require.Equal(t, "true", attrs["sampled"])
+		require.Equal(t, "42", attrs["sample_seed"])
+		require.Equal(t, "1", attrs["sample_rate"])
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "0.5")
+		t.Setenv(quarantine.SampleSeedEnvVar, "nightly-123")
+		decisions := map[bool]int{}
+		for i := 0; i < 5; i++ {
+			fake := runFake(t, func(tb testing.TB) {
+				quarantine.Flaky(tb, "TEST-123")
+			})
+			decisions[fake.Skipped()]++
+		}
+
+		require.Len(t, decisions, 1, "the same seed and test should always make the same decision")
+	})
+
+	t.Run("invalid rate", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "1.5")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+
+		require.True(t, fake.Failed(), "rate above 1 should fail the test")
+	})
+}
@@ -0,0 +1,221 @@
+package quarantine
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// quarantinedTest is a top-level test found to be quarantined by scanning its source.
+type quarantinedTest struct {
+	name   string
+	class  *Classification
+	ticket string
+	// static is true when whether the test runs can be decided without running it, i.e. the test is quarantined
+	// by a top-level call with a literal ticket, and no options that need a runtime decision, like Expires.
+	static bool
+	// registryOnly is true when the test's only use of this package is Check.
+	registryOnly bool
+}
+
+// quarantineFuncs maps the functions that quarantine a whole test to the classification they apply.
+var quarantineFuncs = map[string]*Classification{
+	"Flaky":       FlakyClassification,
+	"FlakyFunc":   FlakyClassification,
+	"Timeout":     TimeoutClassification,
+	"TimeoutFunc": TimeoutClassification,
+}
+
+// usageFuncs are the functions that quarantine a test or part of it, and may be called anywhere in its body.
+var usageFuncs = map[string]bool{
+	"Flaky":        true,
+	"FlakyFunc":    true,
+	"Timeout":      true,
+	"TimeoutFunc":  true,
+	"FlakyWhen":    true,
+	"Classify":     true,
+	"Retry":        true,
+	"Run":          true,
+	"Subtests":     true,
+	"Check":        true,
+	"Stress":       true,
+	"KnownFailure": true,
+	"Tolerate":     true,
+}
+
+// findQuarantinedTests scans the _test.go files in dir for top-level tests that use this package.
+// Tests that call Flaky, FlakyFunc, Timeout, or TimeoutFunc with their own *testing.T are fully quarantined,
+// while other uses, such as quarantined subtests or calls mid-body, are found but can't be decided statically.
+func findQuarantinedTests(dir string) ([]quarantinedTest, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*_test.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		fset  = token.NewFileSet()
+		found []quarantinedTest
+	)
+	for _, file := range files {
+		parsed, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		pkgName, imported := quarantineImportName(parsed)
+		if !imported {
+			continue
+		}
+		for _, decl := range parsed.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || !isTestFunc(fn) {
+				continue
+			}
+			if test, ok := findQuarantineCall(fn, pkgName); ok {
+				found = append(found, test)
+			} else if used := findUsages(fn, pkgName); len(used) > 0 {
+				found = append(found, quarantinedTest{
+					name:         fn.Name.Name,
+					registryOnly: len(used) == 1 && used["Check"],
+				})
+			}
+		}
+	}
+	return found, nil
+}
+
+// quarantineImportName returns the name this package is imported as in the file.
+func quarantineImportName(file *ast.File) (string, bool) {
+	for _, spec := range file.Imports {
+		if path, _ := strconv.Unquote(spec.Path.Value); path != thisPackage {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name, true
+		}
+		return "quarantine", true
+	}
+	return "", false
+}
+
+// isTestFunc reports whether fn looks like func TestXxx(t *testing.T).
+func isTestFunc(fn *ast.FuncDecl) bool {
+	if fn.Recv != nil || fn.Body == nil || len(fn.Type.Params.List) != 1 || len(fn.Type.Params.List[0].Names) != 1 {
+		return false
+	}
+	name, ok := strings.CutPrefix(fn.Name.Name, "Test")
+	if !ok {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(name)
+	return name == "" || !unicode.IsLower(r)
+}
+
+// findQuarantineCall looks for a call quarantining the test with its own *testing.T as the first statement of the test.
+// Only t.Helper and t.Parallel may come before it, as any other statement, such as an if around the call or setup
+// that may fail or skip the test, means the quarantine can't be decided without running the test.
+func findQuarantineCall(fn *ast.FuncDecl, pkgName string) (quarantinedTest, bool) {
+	param := fn.Type.Params.List[0].Names[0].Name
+	for _, stmt := range fn.Body.List {
+		expr, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			return quarantinedTest{}, false
+		}
+		call, ok := expr.X.(*ast.CallExpr)
+		if !ok {
+			return quarantinedTest{}, false
+		}
+		if isTestMethodCall(call, param, "Helper", "Parallel") {
+			continue
+		}
+
+		class, ok := quarantineCall(call, pkgName)
+		if !ok || len(call.Args) < 2 {
+			return quarantinedTest{}, false
+		}
+		if arg, ok := call.Args[0].(*ast.Ident); !ok || arg.Name != param {
+			return quarantinedTest{}, false
+		}
+		test := quarantinedTest{name: fn.Name.Name, class: class, static: true}
+		if lit, ok := call.Args[1].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			test.ticket, _ = strconv.Unquote(lit.Value)
+		} else {
+			test.static = false
+		}
+		if usesFunc(call.Args[2:], pkgName, "Expires") {
+			test.static = false
+		}
+		return test, true
+	}
+	return quarantinedTest{}, false
+}
+
+// isTestMethodCall reports whether call is to one of the named methods of the test's *testing.T.
+func isTestMethodCall(call *ast.CallExpr, param string, methods ...string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	recv, ok := sel.X.(*ast.Ident)
+	return ok && recv.Name == param && slices.Contains(methods, sel.Sel.Name)
+}
+
+// findUsages returns the functions in usageFuncs called anywhere in the test, including closures.
+func findUsages(fn *ast.FuncDecl, pkgName string) map[string]bool {
+	used := map[string]bool{}
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			if name, ok := calledFunc(call, pkgName); ok && usageFuncs[name] {
+				used[name] = true
+			}
+		}
+		return true
+	})
+	return used
+}
+
+// quarantineCall reports whether call is to one of quarantineFuncs, and the classification it applies.
+func quarantineCall(call *ast.CallExpr, pkgName string) (*Classification, bool) {
+	name, ok := calledFunc(call, pkgName)
+	if !ok {
+		return nil, false
+	}
+	class, ok := quarantineFuncs[name]
+	return class, ok
+}
+
+// calledFunc returns the name of the function call calls, if it belongs to this package.
+func calledFunc(call *ast.CallExpr, pkgName string) (string, bool) {
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		if pkg, ok := fun.X.(*ast.Ident); ok && pkg.Name == pkgName {
+			return fun.Sel.Name, true
+		}
+	case *ast.Ident:
+		if pkgName == "." {
+			return fun.Name, true
+		}
+	}
+	return "", false
+}
+
+// usesFunc reports whether any of the expressions call the named function of this package.
+func usesFunc(exprs []ast.Expr, pkgName, funcName string) bool {
+	used := false
+	for _, expr := range exprs {
+		ast.Inspect(expr, func(node ast.Node) bool {
+			if call, ok := node.(*ast.CallExpr); ok {
+				if name, ok := calledFunc(call, pkgName); ok && name == funcName {
+					used = true
+				}
+			}
+			return !used
+		})
+	}
+	return used
+}
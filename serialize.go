@@ -0,0 +1,184 @@
+package quarantine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	// SerializeEnvVar is the environment variable that limits how many enabled quarantined tests run at once
+	// across every test binary sharing the lock dir. "true" runs them one at a time, and a positive number
+	// runs up to that many at a time. It also sets the limit for tests using the Serialize option.
+	SerializeEnvVar = "QUARANTINE_SERIALIZE"
+	// LockDirEnvVar is the environment variable that sets the directory holding the lock files used by
+	// Serialize. It defaults to a quarantine-locks directory in os.TempDir.
+	LockDirEnvVar = "QUARANTINE_LOCK_DIR"
+
+	// slotPollInterval is how often a waiting test retries the lock files.
+	slotPollInterval = 50 * time.Millisecond
+)
+
+var (
+	semaphoresMu sync.Mutex
+	semaphores   = map[string]chan struct{}{}
+	// slotHolders are the names of the tests in this process currently holding a slot.
+	slotHolders = map[string]bool{}
+)
+
+// Serialize limits how many quarantined tests run at once when the test is enabled, so tests that flake under
+// resource contention don't compete with each other when running under go test ./... parallelism.
+// Tests run one at a time unless QUARANTINE_SERIALIZE sets a higher limit. The limit is shared by every test binary
+// through lock files in QUARANTINE_LOCK_DIR, and the time spent waiting is emitted as the serialize_wait attribute.
+func Serialize() Option {
+	return func(o *options) {
+		o.serialize = true
+	}
+}
+
+// slot is a held place among the quarantined tests allowed to run at once.
+type slot struct {
+	semaphore chan struct{}
+	file      *os.File
+}
+
+// acquireSlot blocks until the test may run, and releases its slot once the test finishes.
+// Tests that aren't serialized, isolated subprocesses whose parent already holds a slot, and subtests of a test
+// holding a slot return immediately. A parent only releases its slot after its subtests finish, so a subtest
+// waiting for a slot of its own would deadlock.
+func acquireSlot(tb testing.TB, opts *options) {
+	tb.Helper()
+
+	if isolatedChild(tb) || holdsSlot(tb.Name()) {
+		return
+	}
+	limit, err := serializeLimit(opts)
+	if err != nil {
+		tb.Fatalf("Invalid value for %s: %v", SerializeEnvVar, err)
+		return
+	}
+	if limit == 0 {
+		return
+	}
+
+	start := time.Now()
+	s, err := waitForSlot(lockDir(), limit)
+	if err != nil {
+		tb.Fatalf("Failed to acquire quarantine slot: %v", err)
+		return
+	}
+	attr(tb, "serialize_wait", time.Since(start).String())
+
+	semaphoresMu.Lock()
+	slotHolders[tb.Name()] = true
+	semaphoresMu.Unlock()
+	tb.Cleanup(func() {
+		semaphoresMu.Lock()
+		delete(slotHolders, tb.Name())
+		semaphoresMu.Unlock()
+		s.release()
+	})
+}
+
+// holdsSlot reports whether the named test, or one of its parents, already holds a slot.
+func holdsSlot(testName string) bool {
+	semaphoresMu.Lock()
+	defer semaphoresMu.Unlock()
+
+	for holder := range slotHolders {
+		if isTestOrSubtest(testName, holder) {
+			return true
+		}
+	}
+	return false
+}
+
+// serializeLimit returns how many quarantined tests may run at once, or 0 if the test isn't serialized.
+func serializeLimit(opts *options) (int, error) {
+	value := os.Getenv(SerializeEnvVar)
+	switch value {
+	case "", "true":
+		if value == "" && !opts.serialize {
+			return 0, nil
+		}
+		return 1, nil
+	case "false":
+		return 0, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		return 0, fmt.Errorf("%q is not true, false, or a positive number", value)
+	}
+	return limit, nil
+}
+
+// lockDir returns the directory holding the slot lock files.
+func lockDir() string {
+	if dir := os.Getenv(LockDirEnvVar); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "quarantine-locks")
+}
+
+// waitForSlot takes a place in the in-process semaphore, then polls the slot lock files in dir until one is free.
+func waitForSlot(dir string, limit int) (*slot, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	semaphore := processSemaphore(dir, limit)
+	semaphore <- struct{}{}
+	for {
+		for i := 0; i < limit; i++ {
+			file, ok, err := tryLockSlot(filepath.Join(dir, fmt.Sprintf("slot-%d.lock", i)))
+			if err != nil {
+				<-semaphore
+				return nil, err
+			}
+			if ok {
+				return &slot{semaphore: semaphore, file: file}, nil
+			}
+		}
+		time.Sleep(slotPollInterval)
+	}
+}
+
+// processSemaphore returns the semaphore shared by the tests in this process using the same lock dir and limit.
+func processSemaphore(dir string, limit int) chan struct{} {
+	semaphoresMu.Lock()
+	defer semaphoresMu.Unlock()
+
+	key := dir + "\x00" + strconv.Itoa(limit)
+	semaphore, ok := semaphores[key]
+	if !ok {
+		semaphore = make(chan struct{}, limit)
+		semaphores[key] = semaphore
+	}
+	return semaphore
+}
+
+// tryLockSlot opens the lock file and takes it if it is free. The file is closed if the lock wasn't taken.
+func tryLockSlot(name string) (*os.File, bool, error) {
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_RDWR, 0600) // #nosec G304 - path is built from the lock dir
+	if err != nil {
+		return nil, false, err
+	}
+	ok, err := tryLockFile(file)
+	if err != nil || !ok {
+		_ = file.Close()
+		return nil, false, err
+	}
+	return file, true, nil
+}
+
+// release frees the slot for the next waiting test.
+func (s *slot) release() {
+	_ = unlockFile(s.file)
+	_ = s.file.Close()
+	<-s.semaphore
+}
@@ -0,0 +1,93 @@
+package quarantine_test
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestSerialize(t *testing.T) {
+	t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "true")
+	t.Setenv(quarantine.LockDirEnvVar, t.TempDir())
+
+	// runConcurrently runs tests quarantined with opts at the same time and returns the most that ran at once.
+	runConcurrently := func(t *testing.T, tests int, opts ...quarantine.Option) (int32, []*fakeTB) {
+		var (
+			running, maxRunning atomic.Int32
+			fakes               = make([]*fakeTB, tests)
+			wg                  sync.WaitGroup
+		)
+		for i := range fakes {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				fakes[i] = runNamedFake(t, fmt.Sprintf("%s/%d", t.Name(), i), func(tb testing.TB) {
+					quarantine.Flaky(tb, "TEST-123", opts...)
+					n := running.Add(1)
+					defer running.Add(-1)
+					for {
+						highest := maxRunning.Load()
+						if n <= highest || maxRunning.CompareAndSwap(highest, n) {
+							break
+						}
+					}
+					time.Sleep(50 * time.Millisecond)
+				})
+			}(i)
+		}
+		wg.Wait()
+		return maxRunning.Load(), fakes
+	}
+
+	t.Run("option", func(t *testing.T) {
+		maxRunning, fakes := runConcurrently(t, 4, quarantine.Serialize())
+
+		require.Equal(t, int32(1), maxRunning, "serialized tests should run one at a time")
+		for _, fake := range fakes {
+			require.False(t, fake.Failed())
+			require.Contains(t, fake.Attrs(), "serialize_wait")
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		t.Setenv(quarantine.SerializeEnvVar, "2")
+		maxRunning, _ := runConcurrently(t, 6)
+
+		require.Equal(t, int32(2), maxRunning, "serialized tests should run up to the limit at a time")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, fakes := runConcurrently(t, 2)
+
+		for _, fake := range fakes {
+			require.NotContains(t, fake.Attrs(), "serialize_wait", "tests should not be serialized by default")
+		}
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		t.Setenv(quarantine.SerializeEnvVar, "true")
+		quarantine.Flaky(t, "TEST-1")
+
+		ran := false
+		quarantine.Run(t, "row", "TEST-2", func(*testing.T) {
+			ran = true
+		})
+		require.True(t, ran, "subtest should reuse the slot held by its parent instead of waiting for it")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Setenv(quarantine.SerializeEnvVar, "0")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+
+		require.True(t, fake.Failed(), "invalid limit should fail the test")
+		require.Contains(t, fake.Logs(), "Invalid value for "+quarantine.SerializeEnvVar)
+	})
+}
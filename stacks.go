@@ -0,0 +1,55 @@
+package quarantine
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// ArtifactDirEnvVar is the environment variable that points to a directory quarantine artifacts are written to,
+// such as goroutine dumps of timed out tests.
+const ArtifactDirEnvVar = "QUARANTINE_ARTIFACT_DIR"
+
+// allStacks returns the stack traces of every goroutine.
+func allStacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// writeArtifact writes data to a file named after the test in the artifact dir, and returns its path.
+// It returns an empty path without error when no artifact dir is configured.
+func writeArtifact(tb testing.TB, suffix string, data []byte) (string, error) {
+	artifactDir := os.Getenv(ArtifactDirEnvVar)
+	if artifactDir == "" {
+		return "", nil
+	}
+	if err := os.MkdirAll(artifactDir, 0700); err != nil {
+		return "", err
+	}
+
+	artifactPath := filepath.Join(artifactDir, artifactName(tb.Name())+suffix)
+	if err := os.WriteFile(artifactPath, data, 0600); err != nil {
+		return "", err
+	}
+	return artifactPath, nil
+}
+
+// artifactName makes a test name safe to use as a file name.
+func artifactName(testName string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, testName)
+}
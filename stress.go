@@ -0,0 +1,68 @@
+package quarantine
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// StressEnvVar is the environment variable that makes Stress run test bodies repeatedly.
+const StressEnvVar = "QUARANTINE_STRESS"
+
+// Stress measures how flaky a test body is, to prove a fix before removing its quarantine.
+// When QUARANTINE_STRESS is true, the body runs n times as subtests, up to parallelism at a time,
+// and the pass rate and number of runs are emitted as the pass_rate and runs attributes.
+// Otherwise the test is marked as flaky like Flaky, and the body runs once if quarantined tests are enabled.
+// Like Retry, the body takes a testing.TB, which is the subtest of each run.
+//
+// Example:
+//
+//	func TestFlaky(t *testing.T) {
+//		quarantine.Stress(t, "TEST-123", 100, 4, func(tb testing.TB) {
+//			// Rest of test, using tb instead of t
+//		})
+//	}
+func Stress(t *testing.T, ticket string, n, parallelism int, fn func(tb testing.TB)) {
+	t.Helper()
+
+	if stress, _ := strconv.ParseBool(os.Getenv(StressEnvVar)); !stress {
+		skipTest(t, FlakyClassification, ticket, newOptions(nil))
+		fn(t)
+		return
+	}
+
+	attr(t, FlakyClassification.name, ticket)
+	validateTicket(t, ticket)
+	n = max(n, 1)
+	parallelism = max(parallelism, 1)
+
+	var (
+		failures atomic.Int64
+		sem      = make(chan struct{}, parallelism)
+	)
+	t.Run("stress", func(t *testing.T) {
+		for i := 1; i <= n; i++ {
+			t.Run(fmt.Sprintf("run_%d", i), func(t *testing.T) {
+				if parallelism > 1 {
+					t.Parallel()
+				}
+				sem <- struct{}{}
+				defer func() {
+					<-sem
+					if t.Failed() {
+						failures.Add(1)
+					}
+				}()
+				fn(t)
+			})
+		}
+	})
+
+	passed := n - int(failures.Load())
+	passRate := float64(passed) / float64(n)
+	attr(t, "runs", strconv.Itoa(n))
+	attr(t, "pass_rate", strconv.FormatFloat(passRate, 'f', 4, 64))
+	t.Logf("Stressed test tracked by %s: %d/%d runs passed (%.2f%%)", ticketRef(ticket), passed, n, passRate*100)
+}
@@ -0,0 +1,59 @@
+package quarantine_test
+
+import (
+	"os"
+	"os/exec"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestStress(t *testing.T) {
+	t.Run("behave like flaky", func(t *testing.T) {
+		t.Setenv(quarantine.StressEnvVar, "")
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "true")
+		runs := 0
+		quarantine.Stress(t, "TEST-123", 10, 1, func(testing.TB) {
+			runs++
+		})
+
+		require.Equal(t, 1, runs, "body should run once when not stressing")
+	})
+
+	t.Run("stress", func(t *testing.T) {
+		t.Setenv(quarantine.StressEnvVar, "true")
+		var runs atomic.Int64
+		quarantine.Stress(t, "TEST-123", 20, 4, func(testing.TB) {
+			runs.Add(1)
+		})
+
+		require.EqualValues(t, 20, runs.Load(), "body should run n times when stressing")
+	})
+
+	t.Run("pass rate", func(t *testing.T) {
+		// Failing runs fail the test, so measure them in a subprocess running TestStressFailingHelper
+		cmd := exec.Command(os.Args[0], "-test.run=^TestStressFailingHelper$", "-test.v=true") // #nosec G204
+		cmd.Env = append(os.Environ(), quarantine.StressEnvVar+"=true", "STRESS_FAILING_HELPER=true")
+		output, err := cmd.CombinedOutput()
+
+		require.Error(t, err, "stress with failing runs should fail")
+		require.Contains(t, string(output), "runs 4")
+		require.Contains(t, string(output), "pass_rate 0.5000")
+	})
+}
+
+func TestStressFailingHelper(t *testing.T) {
+	if os.Getenv("STRESS_FAILING_HELPER") != "true" {
+		t.Skip("Only run as a subprocess of TestStress")
+	}
+
+	var runs atomic.Int64
+	quarantine.Stress(t, "TEST-123", 4, 2, func(tb testing.TB) {
+		if runs.Add(1)%2 == 0 {
+			tb.Fatal("flaked")
+		}
+	})
+}
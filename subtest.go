@@ -0,0 +1,89 @@
+package quarantine
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Run is a drop-in replacement for t.Run that marks the subtest as flaky, so only that row of a
+// table-driven test is quarantined. An empty ticket runs the subtest without a quarantine,
+// unless RUN_ONLY_QUARANTINED_TESTS is true, in which case it is skipped.
+//
+// Example:
+//
+//	for _, tc := range tests {
+//		quarantine.Run(t, tc.name, tc.flakyTicket, func(t *testing.T) {
+//			// Rest of subtest
+//		})
+//	}
+func Run(t *testing.T, name, ticket string, fn func(t *testing.T), opts ...Option) bool {
+	t.Helper()
+
+	// The subtest runs on its own goroutine, where the caller's package is no longer on the stack
+	o := newOptions(opts)
+	o.pkg = callerPackage()
+	return t.Run(name, func(t *testing.T) {
+		t.Helper()
+
+		switch {
+		case ticket != "":
+			skipTest(t, FlakyClassification, ticket, o)
+		case onlyQuarantined():
+			t.Skipf("Skipping subtest that isn't quarantined, as %s='true'.", RunOnlyQuarantinedTestsEnvVar)
+		}
+		fn(t)
+	})
+}
+
+// SubtestPolicy quarantines the subtests of a table-driven test whose names match its patterns.
+type SubtestPolicy struct {
+	tickets  map[string]string
+	patterns []string
+}
+
+// Subtests creates a policy that marks subtests as flaky by name.
+// Keys are subtest names as passed to Run, and may be glob patterns. Values are tickets.
+//
+// Example:
+//
+//	policy := quarantine.Subtests(map[string]string{"large input": "TEST-123"})
+//	for _, tc := range tests {
+//		policy.Run(t, tc.name, func(t *testing.T) {
+//			// Rest of subtest
+//		})
+//	}
+func Subtests(tickets map[string]string) *SubtestPolicy {
+	patterns := make([]string, 0, len(tickets))
+	for pattern := range tickets {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	return &SubtestPolicy{tickets: tickets, patterns: patterns}
+}
+
+// Run is a drop-in replacement for t.Run that marks the subtest as flaky if its name matches the policy.
+func (p *SubtestPolicy) Run(t *testing.T, name string, fn func(t *testing.T)) bool {
+	t.Helper()
+
+	return Run(t, name, p.ticket(name), fn)
+}
+
+// ticket returns the ticket of the first pattern matching the subtest name, preferring exact matches.
+func (p *SubtestPolicy) ticket(name string) string {
+	if ticket, ok := p.tickets[name]; ok {
+		return ticket
+	}
+	for _, pattern := range p.patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return p.tickets[pattern]
+		}
+	}
+	return ""
+}
+
+// isTestOrSubtest reports whether testName is the named test or one of its subtests.
+func isTestOrSubtest(testName, name string) bool {
+	return testName == name || strings.HasPrefix(testName, name+"/")
+}
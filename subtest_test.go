@@ -0,0 +1,57 @@
+package quarantine_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestRun(t *testing.T) {
+	t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+
+	ran := map[string]bool{}
+	quarantine.Run(t, "flaky row", "TEST-123", func(t *testing.T) {
+		ran[t.Name()] = true
+	})
+	quarantine.Run(t, "stable row", "", func(t *testing.T) {
+		ran[t.Name()] = true
+	})
+
+	require.Equal(t, map[string]bool{"TestRun/stable_row": true}, ran, "only the stable row should run")
+}
+
+func TestRunOnlyQuarantined(t *testing.T) {
+	t.Setenv(quarantine.RunOnlyQuarantinedTestsEnvVar, "true")
+	t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "")
+
+	ran := map[string]bool{}
+	quarantine.Run(t, "flaky row", "TEST-123", func(t *testing.T) {
+		ran[t.Name()] = true
+	})
+	quarantine.Run(t, "stable row", "", func(t *testing.T) {
+		ran[t.Name()] = true
+	})
+
+	require.Equal(t, map[string]bool{"TestRunOnlyQuarantined/flaky_row": true}, ran, "only the flaky row should run")
+}
+
+func TestSubtests(t *testing.T) {
+	t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+
+	policy := quarantine.Subtests(map[string]string{
+		"exact":    "TEST-1",
+		"large *":  "TEST-2",
+		"unlisted": "TEST-3",
+	})
+
+	ran := map[string]bool{}
+	for _, name := range []string{"exact", "large input", "large output", "small input"} {
+		policy.Run(t, name, func(t *testing.T) {
+			ran[name] = true
+		})
+	}
+
+	require.Equal(t, map[string]bool{"small input": true}, ran, "only subtests not matching the policy should run")
+}
@@ -0,0 +1,233 @@
+package quarantine
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"text/tabwriter"
+	"time"
+)
+
+// summaryEntry is a quarantined test reported in Main's summary.
+type summaryEntry struct {
+	test           string
+	classification string
+	ticket         string
+	result         string
+}
+
+var (
+	mainActive atomic.Bool
+	summaryMu  sync.Mutex
+	summary    []summaryEntry
+)
+
+// Main runs the tests of a package from TestMain, translating quarantines into -test.skip so that
+// expensive setup doesn't even start for tests that would be skipped.
+// It finds quarantined tests by scanning the package's _test.go files for top-level Flaky, Timeout,
+// and TimeoutFunc calls, and prints a summary of the quarantined tests that were skipped or ran.
+// When RUN_ONLY_QUARANTINED_TESTS is true, it instead passes -test.run so only tests that use the
+// quarantine package anywhere in their body run, see RunOnlyQuarantinedTestsEnvVar.
+// It returns the exit code of m.Run.
+//
+// Example:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(quarantine.Main(m))
+//	}
+func Main(m *testing.M) int {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	pkg := callerPackage()
+
+	tests, err := findQuarantinedTests(".")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "quarantine: failed to scan for quarantined tests: %v\n", err)
+	}
+
+	mainActive.Store(true)
+	defer mainActive.Store(false)
+
+	var preSkipped []summaryEntry
+	if onlyQuarantined() {
+		onlyRun(tests, pkg)
+	} else {
+		preSkipped = preSkip(tests)
+		recordPreSkipped(pkg, preSkipped)
+	}
+
+	code := m.Run()
+	printSummary(os.Stdout, pkg, append(preSkipped, takeSummary()...))
+	return code
+}
+
+// onlyRun sets -test.run to the tests that use the quarantine package anywhere in their body.
+// Tests that only call Check are included if the registry lists them.
+func onlyRun(tests []quarantinedTest, pkg string) {
+	if existing := flag.Lookup("test.run"); existing == nil || existing.Value.String() != "" {
+		// Don't override a -test.run the user already chose
+		return
+	}
+
+	var entries []RegistryEntry
+	if registryFile := os.Getenv(RegistryFileEnvVar); registryFile != "" {
+		loaded, err := loadRegistry(registryFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "quarantine: failed to load quarantine registry %s: %v\n", registryFile, err)
+		}
+		entries = loaded
+	}
+
+	var run []quarantinedTest
+	for _, test := range tests {
+		if test.registryOnly && !registryMayMatch(entries, pkg, test.name) {
+			continue
+		}
+		run = append(run, test)
+	}
+	setTestFlag("test.run", testNamesRegex(run))
+}
+
+// preSkip adds the tests that are statically known to be skipped to -test.skip, and returns them.
+func preSkip(tests []quarantinedTest) []summaryEntry {
+	if existing := flag.Lookup("test.skip"); existing == nil || strings.Contains(existing.Value.String(), "/") {
+		// -test.skip patterns are split per subtest level on slashes, so it can't be safely combined
+		return nil
+	}
+
+	var (
+		skipped []summaryEntry
+		names   []quarantinedTest
+	)
+	pattern, err := currentTicketPattern()
+	if err != nil {
+		// The invalid pattern fails every quarantined test once it runs
+		return nil
+	}
+	for _, test := range tests {
+		if !test.static || (test.class.skipInShort && testing.Short()) {
+			continue
+		}
+		if !pattern.MatchString(test.ticket) {
+			// Let the test run so validateTicket fails it
+			continue
+		}
+		mode, err := resolveRunMode(os.Getenv(test.class.envVar), test.ticket, test.name)
+		if err != nil || mode != modeSkip {
+			continue
+		}
+		if _, closed, err := ticketStatus(test.ticket); err != nil || closed {
+			// Closed tickets run the test regardless of the mode, and load errors fail it
+			continue
+		}
+		names = append(names, test)
+		skipped = append(skipped, summaryEntry{
+			test:           test.name,
+			classification: test.class.name,
+			ticket:         test.ticket,
+			result:         "skip (-test.skip)",
+		})
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	skip := testNamesRegex(names)
+	if existing := flag.Lookup("test.skip").Value.String(); existing != "" {
+		skip = "(?:" + existing + ")|" + skip
+	}
+	setTestFlag("test.skip", skip)
+	return skipped
+}
+
+// recordPreSkipped appends a skipped result for each test added to -test.skip, as they never reach recordResult.
+func recordPreSkipped(pkg string, entries []summaryEntry) {
+	resultsFile := os.Getenv(ResultsFileEnvVar)
+	if resultsFile == "" {
+		return
+	}
+	for _, entry := range entries {
+		result := Result{
+			Package:        pkg,
+			Test:           entry.test,
+			Classification: entry.classification,
+			Ticket:         entry.ticket,
+			Skipped:        true,
+			Outcome:        "skip",
+			Time:           time.Now(),
+		}
+		if err := appendResult(resultsFile, result); err != nil {
+			fmt.Fprintf(os.Stderr, "quarantine: failed to write quarantine result to %s: %v\n", resultsFile, err)
+			return
+		}
+	}
+}
+
+// testNamesRegex returns a regex matching exactly the given top-level tests.
+func testNamesRegex(tests []quarantinedTest) string {
+	names := make([]string, 0, len(tests))
+	for _, test := range tests {
+		names = append(names, regexp.QuoteMeta(test.name))
+	}
+	if len(names) == 0 {
+		// Match nothing
+		return "^$"
+	}
+	return "^(?:" + strings.Join(names, "|") + ")$"
+}
+
+func setTestFlag(name, value string) {
+	if err := flag.Set(name, value); err != nil {
+		fmt.Fprintf(os.Stderr, "quarantine: failed to set -%s: %v\n", name, err)
+	}
+}
+
+// recordSummary adds the test to Main's summary once it finishes, if Main is running the tests.
+func recordSummary(tb testing.TB, classification, ticket string) {
+	if !mainActive.Load() || isolatedChild(tb) {
+		return
+	}
+	tb.Cleanup(func() {
+		summaryMu.Lock()
+		defer summaryMu.Unlock()
+		summary = append(summary, summaryEntry{
+			test:           tb.Name(),
+			classification: classification,
+			ticket:         ticket,
+			result:         resultOutcome(tb),
+		})
+	})
+}
+
+func takeSummary() []summaryEntry {
+	summaryMu.Lock()
+	defer summaryMu.Unlock()
+	entries := summary
+	summary = nil
+	return entries
+}
+
+func printSummary(w io.Writer, pkg string, entries []summaryEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].test < entries[j].test
+	})
+
+	fmt.Fprintf(w, "Quarantined tests in %s:\n", pkg)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  TEST\tCLASSIFICATION\tTICKET\tRESULT")
+	for _, entry := range entries {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", entry.test, entry.classification, entry.ticket, entry.result)
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,153 @@
+package quarantine
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const scanFixture = `package fixture_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	q "github.com/smartcontractkit/quarantine"
+)
+
+func TestFlaky(t *testing.T) {
+	t.Parallel()
+	q.Flaky(t, "TEST-1")
+	setup()
+}
+
+func TestTimeout(tt *testing.T) {
+	q.Timeout(tt, "TEST-2", q.Owner("team-a"))
+}
+
+func TestExpiring(t *testing.T) {
+	q.Flaky(t, "TEST-3", q.Expires(time.Now()))
+}
+
+func TestSubtest(t *testing.T) {
+	t.Run("sub", func(t *testing.T) {
+		q.Flaky(t, "TEST-4")
+	})
+}
+
+func TestStable(t *testing.T) {}
+
+func TestRegistered(t *testing.T) {
+	q.Check(t)
+}
+
+func Testlowercase(t *testing.T) {
+	q.Flaky(t, "TEST-5")
+}
+
+func helper(t *testing.T) {
+	q.Flaky(t, "TEST-6")
+}
+
+func TestConditional(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		q.Flaky(t, "TEST-7")
+	}
+}
+
+func TestSetupFirst(t *testing.T) {
+	setup()
+	q.Flaky(t, "TEST-8")
+}
+`
+
+func TestFindQuarantinedTests(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fixture_test.go"), []byte(scanFixture), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fixture.go"), []byte("package fixture\n"), 0600))
+
+	tests, err := findQuarantinedTests(dir)
+	require.NoError(t, err)
+	require.Equal(t, []quarantinedTest{
+		{name: "TestFlaky", class: FlakyClassification, ticket: "TEST-1", static: true},
+		{name: "TestTimeout", class: TimeoutClassification, ticket: "TEST-2", static: true},
+		{name: "TestExpiring", class: FlakyClassification, ticket: "TEST-3", static: false},
+		{name: "TestSubtest"},
+		{name: "TestRegistered", registryOnly: true},
+		{name: "TestConditional"},
+		{name: "TestSetupFirst"},
+	}, tests)
+}
+
+func TestPreSkip(t *testing.T) {
+	skipFlag := flag.Lookup("test.skip")
+	original := skipFlag.Value.String()
+	t.Cleanup(func() { setTestFlag("test.skip", original) })
+	t.Setenv(FlakyClassification.envVar, "false")
+
+	statusFile := filepath.Join(t.TempDir(), "statuses.json")
+	require.NoError(t, os.WriteFile(statusFile, []byte(`{"TEST-1": "In Progress", "TEST-2": "Closed"}`), 0600))
+	t.Setenv(TicketStatusFileEnvVar, statusFile)
+
+	skipped := preSkip([]quarantinedTest{
+		{name: "TestOpen", class: FlakyClassification, ticket: "TEST-1", static: true},
+		{name: "TestClosed", class: FlakyClassification, ticket: "TEST-2", static: true},
+		{name: "TestMalformed", class: FlakyClassification, ticket: "flaky", static: true},
+	})
+
+	require.Len(t, skipped, 1)
+	require.Equal(t, "TestOpen", skipped[0].test)
+	skip := regexp.MustCompile(skipFlag.Value.String())
+	require.True(t, skip.MatchString("TestOpen"))
+	require.False(t, skip.MatchString("TestClosed"), "tests with closed tickets should run to check the quarantine")
+	require.False(t, skip.MatchString("TestMalformed"), "tests with invalid tickets should run to fail validation")
+}
+
+func TestRecordPreSkipped(t *testing.T) {
+	resultsFile := filepath.Join(t.TempDir(), "results.jsonl")
+	t.Setenv(ResultsFileEnvVar, resultsFile)
+
+	recordPreSkipped("github.com/org/repo", []summaryEntry{
+		{test: "TestA", classification: "flaky", ticket: "TEST-1", result: "skip (-test.skip)"},
+	})
+
+	data, err := os.ReadFile(resultsFile)
+	require.NoError(t, err)
+	var result Result
+	require.NoError(t, json.Unmarshal(data, &result))
+	require.Equal(t, "github.com/org/repo", result.Package)
+	require.Equal(t, "TestA", result.Test)
+	require.Equal(t, "TEST-1", result.Ticket)
+	require.True(t, result.Skipped)
+	require.Equal(t, "skip", result.Outcome)
+}
+
+func TestTestNamesRegex(t *testing.T) {
+	re := regexp.MustCompile(testNamesRegex([]quarantinedTest{{name: "TestA"}, {name: "TestB"}}))
+
+	require.True(t, re.MatchString("TestA"))
+	require.True(t, re.MatchString("TestB"))
+	require.False(t, re.MatchString("TestAB"))
+	require.False(t, regexp.MustCompile(testNamesRegex(nil)).MatchString("TestA"), "no tests should match nothing")
+}
+
+func TestPrintSummary(t *testing.T) {
+	var out strings.Builder
+	printSummary(&out, "github.com/org/repo", []summaryEntry{
+		{test: "TestB", classification: "timeout", ticket: "TEST-2", result: "pass"},
+		{test: "TestA", classification: "flaky", ticket: "TEST-1", result: "skip (-test.skip)"},
+	})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, "Quarantined tests in github.com/org/repo:", lines[0])
+	require.Contains(t, lines[2], "TestA")
+	require.Contains(t, lines[3], "TestB")
+}
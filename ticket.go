@@ -0,0 +1,87 @@
+package quarantine
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	// TicketPatternEnvVar is the environment variable that overrides the regex tickets must match.
+	TicketPatternEnvVar = "QUARANTINE_TICKET_PATTERN"
+	// TicketURLTemplateEnvVar is the environment variable that overrides the ticket URL template.
+	TicketURLTemplateEnvVar = "QUARANTINE_TICKET_URL_TEMPLATE"
+
+	// ticketPlaceholder is replaced by the ticket in URL templates.
+	ticketPlaceholder = "{ticket}"
+)
+
+// DefaultTicketPattern accepts JIRA keys like TEST-123, and GitHub issue references like #123 or org/repo#123.
+var DefaultTicketPattern = regexp.MustCompile(`^(?:[A-Z][A-Z0-9_]*-[0-9]+|(?:[\w.-]+/[\w.-]+)?#[0-9]+)$`)
+
+var (
+	ticketPattern     atomic.Pointer[regexp.Regexp]
+	ticketURLTemplate atomic.Pointer[string]
+)
+
+// SetTicketPattern sets the regex tickets must match, tests with other tickets fail.
+// Passing nil restores DefaultTicketPattern. QUARANTINE_TICKET_PATTERN takes precedence when set.
+func SetTicketPattern(pattern *regexp.Regexp) {
+	ticketPattern.Store(pattern)
+}
+
+// SetTicketURLTemplate sets a URL template used to link tickets in skip messages and the ticket_url attribute.
+// Every {ticket} in the template is replaced by the ticket, e.g. "https://example.atlassian.net/browse/{ticket}".
+// An empty template disables links. QUARANTINE_TICKET_URL_TEMPLATE takes precedence when set.
+func SetTicketURLTemplate(template string) {
+	ticketURLTemplate.Store(&template)
+}
+
+// validateTicket fails the test if the ticket doesn't match the configured pattern.
+func validateTicket(tb testing.TB, ticket string) {
+	tb.Helper()
+
+	pattern, err := currentTicketPattern()
+	if err != nil {
+		tb.Fatalf("Invalid %s: %v", TicketPatternEnvVar, err)
+		return
+	}
+	if !pattern.MatchString(ticket) {
+		tb.Fatalf("Invalid quarantine ticket %q, tickets must match %s", ticket, pattern)
+	}
+}
+
+func currentTicketPattern() (*regexp.Regexp, error) {
+	if pattern := os.Getenv(TicketPatternEnvVar); pattern != "" {
+		return regexp.Compile(pattern)
+	}
+	if pattern := ticketPattern.Load(); pattern != nil {
+		return pattern, nil
+	}
+	return DefaultTicketPattern, nil
+}
+
+// ticketURL returns the link to the ticket, or an empty string if no template is configured.
+func ticketURL(ticket string) string {
+	template := os.Getenv(TicketURLTemplateEnvVar)
+	if template == "" {
+		if stored := ticketURLTemplate.Load(); stored != nil {
+			template = *stored
+		}
+	}
+	if template == "" {
+		return ""
+	}
+	return strings.ReplaceAll(template, ticketPlaceholder, ticket)
+}
+
+// ticketRef describes a ticket for messages, including its link when one is configured.
+func ticketRef(ticket string) string {
+	if url := ticketURL(ticket); url != "" {
+		return fmt.Sprintf("%s (%s)", ticket, url)
+	}
+	return ticket
+}
@@ -0,0 +1,92 @@
+package quarantine_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestTicketValidation(t *testing.T) {
+	tests := []struct {
+		ticket string
+		valid  bool
+	}{
+		{ticket: "TEST-123", valid: true},
+		{ticket: "DX_2-7", valid: true},
+		{ticket: "#123", valid: true},
+		{ticket: "smartcontractkit/quarantine#42", valid: true},
+		{ticket: "", valid: false},
+		{ticket: "flaky", valid: false},
+		{ticket: "test-123", valid: false},
+		{ticket: "#abc", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.ticket, func(t *testing.T) {
+			t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+			fake := runFake(t, func(tb testing.TB) {
+				quarantine.Flaky(tb, test.ticket)
+			})
+
+			require.Equal(t, !test.valid, fake.Failed(), "unexpected validation result for %q", test.ticket)
+		})
+	}
+
+	t.Run("env pattern", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		t.Setenv(quarantine.TicketPatternEnvVar, `^flaky-\d+$`)
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "flaky-1")
+		})
+
+		require.False(t, fake.Failed(), "ticket matching the env pattern should be accepted")
+	})
+
+	t.Run("set pattern", func(t *testing.T) {
+		quarantine.SetTicketPattern(regexp.MustCompile(`^BUG\d+$`))
+		t.Cleanup(func() { quarantine.SetTicketPattern(nil) })
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+
+		require.True(t, fake.Failed(), "ticket not matching the configured pattern should be rejected")
+		require.Contains(t, fake.Logs(), `Invalid quarantine ticket "TEST-123"`)
+	})
+}
+
+func TestTicketURL(t *testing.T) {
+	t.Run("env template", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		t.Setenv(quarantine.TicketURLTemplateEnvVar, "https://example.atlassian.net/browse/{ticket}")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+
+		require.Equal(t, "https://example.atlassian.net/browse/TEST-123", fake.Attrs()["ticket_url"])
+		require.Contains(t, fake.Logs(), "TEST-123 (https://example.atlassian.net/browse/TEST-123)")
+	})
+
+	t.Run("set template", func(t *testing.T) {
+		quarantine.SetTicketURLTemplate("https://tracker.example.com/{ticket}")
+		t.Cleanup(func() { quarantine.SetTicketURLTemplate("") })
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+
+		require.Equal(t, "https://tracker.example.com/TEST-123", fake.Attrs()["ticket_url"])
+	})
+
+	t.Run("no template", func(t *testing.T) {
+		t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123")
+		})
+
+		require.NotContains(t, fake.Attrs(), "ticket_url")
+	})
+}
@@ -0,0 +1,83 @@
+package quarantine
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TicketStatusFileEnvVar is the environment variable that points to a JSON snapshot of ticket statuses,
+// mapping each ticket to its status, e.g. {"TEST-123": "Closed"}.
+const TicketStatusFileEnvVar = "QUARANTINE_TICKET_STATUS_FILE"
+
+// resolvedStatuses are the ticket statuses, in lower case, that mean the ticket is closed.
+var resolvedStatuses = map[string]bool{
+	"closed":   true,
+	"resolved": true,
+	"done":     true,
+	"fixed":    true,
+	"wontfix":  true,
+}
+
+var ticketStatusFiles = newFileCache(parseTicketStatuses)
+
+// checkClosedTicket looks the ticket up in the ticket status snapshot, if one is configured.
+// When the ticket is closed, the quarantine is stale: the caller runs the test regardless of its env var,
+// and it fails if it passes so that the quarantine gets removed. Returns true if the ticket is closed.
+func checkClosedTicket(tb testing.TB, ticket string) bool {
+	tb.Helper()
+
+	status, closed, err := ticketStatus(ticket)
+	if err != nil {
+		tb.Fatalf("Failed to load ticket statuses %s: %v", os.Getenv(TicketStatusFileEnvVar), err)
+		return false
+	}
+	if status != "" {
+		attr(tb, "ticket_status", status)
+	}
+	if !closed {
+		return false
+	}
+
+	tb.Logf(
+		"Ticket %s is %s, running the test to check whether its quarantine can be removed.",
+		ticketRef(ticket),
+		status,
+	)
+	tb.Cleanup(func() {
+		switch {
+		case tb.Skipped():
+			return
+		case tb.Failed():
+			tb.Logf("Ticket %s is %s, but the test still fails. Reopen the ticket.", ticket, status)
+			return
+		}
+		tb.Errorf("Ticket %s is %s, remove the quarantine.", ticket, strings.ToLower(status))
+	})
+	return true
+}
+
+// ticketStatus returns the status of the ticket in the ticket status snapshot, if one is configured,
+// and whether that status means the ticket is closed.
+func ticketStatus(ticket string) (string, bool, error) {
+	statusFile := os.Getenv(TicketStatusFileEnvVar)
+	if statusFile == "" {
+		return "", false, nil
+	}
+	statuses, err := ticketStatusFiles.load(statusFile)
+	if err != nil {
+		return "", false, err
+	}
+	status := statuses[ticket]
+	return status, resolvedStatuses[strings.ToLower(status)], nil
+}
+
+// parseTicketStatuses parses a ticket status file.
+func parseTicketStatuses(data []byte) (map[string]string, error) {
+	var statuses map[string]string
+	if err := json.Unmarshal(data, &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
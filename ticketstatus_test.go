@@ -0,0 +1,67 @@
+package quarantine_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestTicketStatus(t *testing.T) {
+	statusFile := filepath.Join(t.TempDir(), "statuses.json")
+	require.NoError(t, os.WriteFile(statusFile, []byte(`{"TEST-1": "Closed", "TEST-2": "In Progress"}`), 0600))
+	t.Setenv(quarantine.TicketStatusFileEnvVar, statusFile)
+	t.Setenv(quarantine.RunQuarantinedTestsEnvVar, "false")
+
+	t.Run("fail passing test with closed ticket", func(t *testing.T) {
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-1")
+		})
+
+		require.False(t, fake.Skipped(), "test with a closed ticket should run")
+		require.True(t, fake.Failed(), "passing test with a closed ticket should fail")
+		require.Contains(t, fake.Logs(), "Ticket TEST-1 is closed, remove the quarantine.")
+		require.Equal(t, "Closed", fake.Attrs()["ticket_status"])
+	})
+
+	t.Run("keep failure of test with closed ticket", func(t *testing.T) {
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-1")
+			tb.Error("still broken")
+		})
+
+		require.True(t, fake.Failed())
+		require.Contains(t, fake.Logs(), "Reopen the ticket")
+	})
+
+	t.Run("run test with closed ticket like any enabled test", func(t *testing.T) {
+		t.Setenv(quarantine.SerializeEnvVar, "true")
+		t.Setenv(quarantine.LockDirEnvVar, t.TempDir())
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-1")
+		})
+
+		require.Contains(t, fake.Attrs(), "serialize_wait", "test with a closed ticket should still be serialized")
+		require.Contains(t, fake.Attrs(), "leaked_goroutines", "test with a closed ticket should still be leak checked")
+	})
+
+	t.Run("skip test with open ticket", func(t *testing.T) {
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-2")
+		})
+
+		require.True(t, fake.Skipped(), "test with an open ticket should be skipped")
+		require.Equal(t, "In Progress", fake.Attrs()["ticket_status"])
+	})
+
+	t.Run("skip test with unknown ticket", func(t *testing.T) {
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-3")
+		})
+
+		require.True(t, fake.Skipped(), "test with an unknown ticket should be skipped")
+	})
+}
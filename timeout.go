@@ -0,0 +1,102 @@
+package quarantine
+
+import (
+	"testing"
+	"time"
+)
+
+// TimeoutFunc marks a test as expected to timeout like Timeout, and when it runs, runs its body with its own deadline.
+// If the body doesn't finish within d, every goroutine's stack is dumped to the test log
+// (and to QUARANTINE_ARTIFACT_DIR if set), and the test fails with a timed_out attribute,
+// instead of go test's -timeout panicking and losing every other test in the package.
+// The body keeps running in the background after the deadline, as goroutines can't be stopped, but stops reporting
+// attributes or setting env vars on the test. If the test's own -timeout deadline comes first, d is cut short
+// to leave time for the dump.
+//
+// Example:
+//
+//	func TestHangs(t *testing.T) {
+//		quarantine.TimeoutFunc(t, "TEST-123", time.Minute, func(tb testing.TB) {
+//			// Rest of test, using tb instead of t
+//		})
+//	}
+func TimeoutFunc(tb testing.TB, ticket string, d time.Duration, fn func(tb testing.TB), opts ...Option) {
+	tb.Helper()
+
+	skipTest(tb, TimeoutClassification, ticket, newOptions(opts))
+
+	if capped, ok := capTimeout(tb, d); ok {
+		tb.Logf("Cutting timeout of %s to %s to finish before the go test -timeout deadline", d, capped)
+		d = capped
+	}
+	rec, done := startRecording(tb, fn)
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		replay(tb, rec)
+	case <-timer.C:
+		rec.abandon()
+		stacks := allStacks()
+		attr(tb, "timed_out", d.String())
+		if artifactPath, err := writeArtifact(tb, ".goroutines.txt", stacks); err != nil {
+			tb.Logf("Failed to write goroutine dump: %v", err)
+		} else if artifactPath != "" {
+			attr(tb, "goroutine_dump", artifactPath)
+		}
+		tb.Errorf(
+			"Test tracked by %s timed out after %s.\nOutput so far:\n%s\n\nGoroutine dump:\n%s",
+			ticketRef(ticket),
+			d,
+			rec.output(),
+			stacks,
+		)
+	}
+}
+
+// timeoutMargin is how long before the go test -timeout deadline a TimeoutFunc body is given up on.
+const timeoutMargin = 5 * time.Second
+
+// deadliner is implemented by *testing.T, but not by testing.TB.
+type deadliner interface {
+	Deadline() (time.Time, bool)
+}
+
+// capTimeout returns a timeout ending timeoutMargin before the test's deadline, if d would end after it.
+// With less than twice the margin left, it gives the body half of the remaining time instead.
+func capTimeout(tb testing.TB, d time.Duration) (time.Duration, bool) {
+	t, ok := tb.(deadliner)
+	if !ok {
+		return d, false
+	}
+	deadline, ok := t.Deadline()
+	if !ok {
+		return d, false
+	}
+
+	remaining := time.Until(deadline)
+	capped := remaining - timeoutMargin
+	if capped < remaining/2 {
+		capped = remaining / 2
+	}
+	if capped >= d {
+		return d, false
+	}
+	return capped, true
+}
+
+// replay reports what a finished recorder captured on the real test.
+func replay(tb testing.TB, rec *recorder) {
+	tb.Helper()
+
+	output := rec.output()
+	switch {
+	case rec.Skipped():
+		tb.Skip(output)
+	case rec.Failed():
+		tb.Error(output)
+	case output != "":
+		tb.Log(output)
+	}
+}
@@ -0,0 +1,118 @@
+package quarantine_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestTimeoutFunc(t *testing.T) {
+	t.Run("skip timeout tests", func(t *testing.T) {
+		t.Setenv(quarantine.RunTimeoutTestsEnvVar, "false")
+		ran := false
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.TimeoutFunc(tb, "TEST-123", time.Second, func(testing.TB) {
+				ran = true
+			})
+		})
+
+		require.True(t, fake.Skipped(), "timeout test should be skipped when RUN_TIMEOUT_TESTS is false")
+		require.False(t, ran, "body should not run when skipped")
+	})
+
+	t.Run("finish in time", func(t *testing.T) {
+		t.Setenv(quarantine.RunTimeoutTestsEnvVar, "true")
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.TimeoutFunc(tb, "TEST-123", time.Minute, func(tb testing.TB) {
+				tb.Log("done")
+			})
+		})
+
+		require.False(t, fake.Failed(), "body that finishes in time should pass")
+		require.NotContains(t, fake.Attrs(), "timed_out")
+		require.Contains(t, fake.Logs(), "done")
+	})
+
+	t.Run("time out", func(t *testing.T) {
+		t.Setenv(quarantine.RunTimeoutTestsEnvVar, "true")
+		artifactDir := t.TempDir()
+		t.Setenv(quarantine.ArtifactDirEnvVar, artifactDir)
+		hang := make(chan struct{})
+		t.Cleanup(func() { close(hang) })
+
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.TimeoutFunc(tb, "TEST-123", 10*time.Millisecond, func(tb testing.TB) {
+				tb.Log("started")
+				<-hang
+			})
+		})
+
+		require.True(t, fake.Failed(), "body that hangs should fail the test")
+		attrs := fake.Attrs()
+		require.Equal(t, "10ms", attrs["timed_out"])
+		require.Contains(t, fake.Logs(), "timed out after 10ms")
+		require.Contains(t, fake.Logs(), "goroutine")
+
+		dump, err := os.ReadFile(attrs["goroutine_dump"])
+		require.NoError(t, err, "goroutine dump should be written to the artifact dir")
+		require.Contains(t, string(dump), "goroutine")
+	})
+
+	t.Run("cap to test deadline", func(t *testing.T) {
+		t.Setenv(quarantine.RunTimeoutTestsEnvVar, "true")
+		hang := make(chan struct{})
+		t.Cleanup(func() { close(hang) })
+
+		fake := runFake(t, func(tb testing.TB) {
+			tb = deadlineTB{TB: tb, deadline: time.Now().Add(100 * time.Millisecond)}
+			quarantine.TimeoutFunc(tb, "TEST-123", time.Minute, func(testing.TB) {
+				<-hang
+			})
+		})
+
+		require.True(t, fake.Failed(), "body that hangs should fail the test")
+		timedOut, err := time.ParseDuration(fake.Attrs()["timed_out"])
+		require.NoError(t, err)
+		require.Less(t, timedOut, 100*time.Millisecond, "timeout should end before the test deadline")
+		require.Contains(t, fake.Logs(), "Cutting timeout of 1m0s")
+	})
+
+	t.Run("abandoned body", func(t *testing.T) {
+		t.Setenv(quarantine.RunTimeoutTestsEnvVar, "true")
+		const envVar = "QUARANTINE_TEST_ABANDONED"
+		release := make(chan struct{})
+		finished := make(chan string)
+
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.TimeoutFunc(tb, "TEST-123", 10*time.Millisecond, func(tb testing.TB) {
+				<-release
+				tb.(interface{ Attr(key, value string) }).Attr("late", "true")
+				tb.Setenv(envVar, "true")
+				finished <- tb.TempDir()
+			})
+		})
+		close(release)
+		dir := <-finished
+
+		require.True(t, fake.Failed(), "body that hangs should fail the test")
+		require.NotContains(t, fake.Attrs(), "late", "attributes from an abandoned body should be dropped")
+		_, set := os.LookupEnv(envVar)
+		require.False(t, set, "env vars from an abandoned body should be dropped")
+		require.Eventually(t, func() bool {
+			_, err := os.Stat(dir)
+			return os.IsNotExist(err)
+		}, time.Second, 10*time.Millisecond, "temp dir of an abandoned body should be removed once it finishes")
+	})
+}
+
+// deadlineTB is a testing.TB reporting the given deadline, like a *testing.T run with -timeout.
+type deadlineTB struct {
+	testing.TB
+	deadline time.Time
+}
+
+func (d deadlineTB) Deadline() (time.Time, bool) { return d.deadline, true }
@@ -0,0 +1,140 @@
+package quarantine
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// SignaturesFileEnvVar is the environment variable that points to a file of known flake signatures.
+// The file holds one regex per line, blank lines and lines starting with # are ignored.
+const SignaturesFileEnvVar = "QUARANTINE_SIGNATURES_FILE"
+
+var signatureFiles = newFileCache(parseSignatures)
+
+// Signatures adds regexes matching the failure messages of known flakes. It only applies to Tolerate,
+// other quarantines fail the test if it is passed.
+func Signatures(patterns ...string) Option {
+	return func(o *options) {
+		o.signatures = append(o.signatures, patterns...)
+	}
+}
+
+// Tolerate runs the body of a flaky test and tolerates only the failures it already knows about.
+// Options that decide whether the test runs, Expires, Isolate, Serialize, and conditions, fail the test.
+// Each failure message reported through Error, Errorf, Fatal, or Fatalf is matched against the Signatures option
+// and the signatures file pointed to by QUARANTINE_SIGNATURES_FILE. If every failure matches a signature, the test
+// is skipped and the first matched signature is emitted as the flake_signature attribute.
+// Any other failure, including a bare Fail, still fails the test, so new regressions aren't hidden by the quarantine.
+//
+// Example:
+//
+//	func TestFlaky(t *testing.T) {
+//		quarantine.Tolerate(t, "TEST-123", func(tb testing.TB) {
+//			// Rest of test, using tb instead of t
+//		}, quarantine.Signatures(`connection reset by peer`, `context deadline exceeded`))
+//	}
+func Tolerate(tb testing.TB, ticket string, fn func(tb testing.TB), opts ...Option) {
+	tb.Helper()
+
+	o := newOptions(opts)
+	attr(tb, FlakyClassification.name, ticket)
+	o.emitAttrs(tb)
+	validateTicket(tb, ticket)
+	if unsupported := o.gatingOptions(); len(unsupported) > 0 {
+		tb.Fatalf("Tolerate always runs the test, so it doesn't support %s", strings.Join(unsupported, ", "))
+		return
+	}
+
+	signatures, err := loadSignatures(o.signatures)
+	if err != nil {
+		tb.Fatalf("Invalid flake signatures: %v", err)
+		return
+	}
+
+	rec := record(tb, fn)
+	if rec.Skipped() {
+		tb.Skip(rec.output())
+		return
+	}
+	if !rec.Failed() {
+		if output := rec.output(); output != "" {
+			tb.Log(output)
+		}
+		return
+	}
+
+	failures := rec.failureMessages()
+	matched, ok := matchSignatures(signatures, failures)
+	if !ok {
+		tb.Log(rec.output())
+		tb.Errorf("Failure does not match a known flake signature for flaky test tracked by %s", ticketRef(ticket))
+		return
+	}
+	attr(tb, "flake_signature", matched.String())
+	tb.Skipf("Tolerated known flake tracked by %s matching %q:\n%s", ticketRef(ticket), matched, rec.output())
+}
+
+// matchSignatures returns the signature matching the first failure, if every failure matches one of the signatures.
+// A failure without a message can't be matched, so a body that failed without any messages never matches.
+func matchSignatures(signatures []*regexp.Regexp, failures []string) (*regexp.Regexp, bool) {
+	if len(failures) == 0 {
+		return nil, false
+	}
+
+	var first *regexp.Regexp
+	for _, failure := range failures {
+		var matched *regexp.Regexp
+		for _, signature := range signatures {
+			if signature.MatchString(failure) {
+				matched = signature
+				break
+			}
+		}
+		if matched == nil {
+			return nil, false
+		}
+		if first == nil {
+			first = matched
+		}
+	}
+	return first, true
+}
+
+// loadSignatures compiles the given patterns along with those in the signatures file, if one is set.
+func loadSignatures(patterns []string) ([]*regexp.Regexp, error) {
+	if signaturesFile := os.Getenv(SignaturesFileEnvVar); signaturesFile != "" {
+		filePatterns, err := signatureFiles.load(signaturesFile)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns[:len(patterns):len(patterns)], filePatterns...)
+	}
+
+	signatures := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		signature, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, signature)
+	}
+	return signatures, nil
+}
+
+// parseSignatures returns the patterns in a signatures file.
+func parseSignatures(data []byte) ([]string, error) {
+	var patterns []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
@@ -0,0 +1,80 @@
+package quarantine_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/quarantine"
+)
+
+func TestTolerate(t *testing.T) {
+	t.Run("matching failure", func(t *testing.T) {
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Tolerate(tb, "TEST-123", func(tb testing.TB) {
+				tb.Fatal("dial tcp: connection reset by peer")
+			}, quarantine.Signatures(`connection reset by peer`))
+		})
+
+		require.True(t, fake.Skipped(), "failure matching a signature should be skipped")
+		require.False(t, fake.Failed(), "failure matching a signature should not fail the test")
+		require.Equal(t, "connection reset by peer", fake.Attrs()["flake_signature"])
+	})
+
+	t.Run("non-matching failure", func(t *testing.T) {
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Tolerate(tb, "TEST-123", func(tb testing.TB) {
+				tb.Error("dial tcp: connection reset by peer")
+				tb.Error("expected 2, got 3")
+			}, quarantine.Signatures(`connection reset by peer`))
+		})
+
+		require.True(t, fake.Failed(), "failure not matching a signature should fail the test")
+		require.Contains(t, fake.Logs(), "expected 2, got 3")
+		require.NotContains(t, fake.Attrs(), "flake_signature")
+	})
+
+	t.Run("signatures file", func(t *testing.T) {
+		signaturesFile := filepath.Join(t.TempDir(), "signatures.txt")
+		contents := "# Known flakes\n\ncontext deadline exceeded\n"
+		require.NoError(t, os.WriteFile(signaturesFile, []byte(contents), 0600))
+		t.Setenv(quarantine.SignaturesFileEnvVar, signaturesFile)
+
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Tolerate(tb, "TEST-123", func(tb testing.TB) {
+				tb.Fatalf("waiting for block: %s", "context deadline exceeded")
+			})
+		})
+
+		require.True(t, fake.Skipped(), "failure matching a signature from the file should be skipped")
+		require.Equal(t, "context deadline exceeded", fake.Attrs()["flake_signature"])
+	})
+
+	t.Run("reject gating options", func(t *testing.T) {
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Tolerate(tb, "TEST-123", func(testing.TB) {}, quarantine.Isolate(), quarantine.Owner("team-a"))
+		})
+
+		require.True(t, fake.Failed(), "options Tolerate ignores should fail the test")
+		require.Contains(t, fake.Logs(), "doesn't support Isolate")
+	})
+
+	t.Run("reject signatures on other quarantines", func(t *testing.T) {
+		fake := runFake(t, func(tb testing.TB) {
+			quarantine.Flaky(tb, "TEST-123", quarantine.Signatures(`timeout`))
+		})
+
+		require.True(t, fake.Failed(), "Signatures passed to Flaky should fail the test")
+		require.Contains(t, fake.Logs(), "only applies to Tolerate")
+	})
+
+	t.Run("pass", func(t *testing.T) {
+		quarantine.Tolerate(t, "TEST-123", func(testing.TB) {}, quarantine.Signatures(`timeout`))
+
+		t.Cleanup(func() {
+			require.False(t, t.Skipped(), "passing body should not be skipped")
+		})
+	})
+}